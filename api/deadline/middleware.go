@@ -0,0 +1,36 @@
+// Package deadline bounds the time handlers may spend on a request, so that
+// repository and RPC calls made on its behalf stop once it has run out of time.
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const contextParent = "deadlineParent"
+
+// Middleware cancels the request context timeout after the request arrives.
+// Handlers see the deadline through ctx.Request.Context().
+func Middleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parent := c.Request.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+
+		c.Set(contextParent, parent)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Lift removes the deadline set by Middleware from routes that run as long as the
+// client reads, such as streams. The request context is still canceled when the
+// client goes away.
+func Lift(c *gin.Context) {
+	if parent, ok := c.Get(contextParent); ok {
+		c.Request = c.Request.WithContext(parent.(context.Context))
+	}
+	c.Next()
+}
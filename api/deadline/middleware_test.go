@@ -0,0 +1,36 @@
+package deadline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Middleware(time.Minute))
+
+	var limited, lifted bool
+	engine.GET("/short", func(c *gin.Context) {
+		_, limited = c.Request.Context().Deadline()
+	})
+	engine.GET("/stream", Lift, func(c *gin.Context) {
+		_, lifted = c.Request.Context().Deadline()
+		lifted = !lifted
+	})
+
+	t.Run("Set a deadline on the request context", func(t *testing.T) {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/short", nil))
+		assert.True(t, limited)
+	})
+
+	t.Run("Lift the deadline", func(t *testing.T) {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/stream", nil))
+		assert.True(t, lifted)
+	})
+}
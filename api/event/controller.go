@@ -0,0 +1,57 @@
+// Package eventapi exposes the calendar of limited-time game modes.
+package eventapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+)
+
+// EventController handles event calendar requests.
+type EventController struct {
+	eventRepo i.EventRepo
+}
+
+// NewEventController initializes an EventController.
+func NewEventController(er i.EventRepo) *EventController {
+	return &EventController{
+		eventRepo: er,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (ec *EventController) RegisterPublic(route *gin.RouterGroup) {
+	route.GET("/events", ec.list)
+}
+
+// RegisterProtected registers protected routes.
+func (ec *EventController) RegisterProtected(route *gin.RouterGroup) {}
+
+// list returns running and upcoming events.
+func (ec *EventController) list(ctx *gin.Context) {
+	now := time.Now()
+	events, err := ec.eventRepo.EndingAfter(ctx.Request.Context(), now)
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching events")
+		return
+	}
+
+	res := make([]*EventResponse, 0, len(events))
+	for _, e := range events {
+		res = append(res, &EventResponse{
+			ID:          e.ID,
+			Name:        e.Name,
+			Description: e.Description,
+			Mode:        e.Mode,
+			Modifiers:   e.Modifiers,
+			StartsAt:    e.StartsAt,
+			EndsAt:      e.EndsAt,
+			Active:      e.ActiveAt(now),
+		})
+	}
+
+	response.OK(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,20 @@
+// Package eventapi provides structures and utilities for limited-time event responses.
+package eventapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventResponse represents a running or upcoming event.
+type EventResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Mode        string             `json:"mode"`
+	Modifiers   map[string]float64 `json:"modifiers"`
+	StartsAt    time.Time          `json:"startsAt"`
+	EndsAt      time.Time          `json:"endsAt"`
+	Active      bool               `json:"active"`
+}
@@ -0,0 +1,144 @@
+// Package friendsapi manages friend requests and friendships.
+package friendsapi
+
+import (
+	"net/http"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FriendsController handles friend requests and friendships of the authenticated user.
+type FriendsController struct {
+	friends     i.Friends
+	middlewares []gin.HandlerFunc
+}
+
+// NewFriendsController initializes a FriendsController.
+// The given middlewares run before every /friends route, e.g. rate limiters.
+func NewFriendsController(f i.Friends, middlewares ...gin.HandlerFunc) *FriendsController {
+	return &FriendsController{
+		friends:     f,
+		middlewares: middlewares,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (fc *FriendsController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+func (fc *FriendsController) RegisterProtected(route *gin.RouterGroup) {
+	friends := route.Group("/friends", identity.Requires("role:player"))
+	friends.Use(fc.middlewares...)
+	{
+		friends.GET("/", fc.list)
+		friends.POST("/", fc.request)
+		friends.POST("/:ID/accept", fc.accept)
+		friends.DELETE("/:ID", fc.remove)
+	}
+}
+
+// list returns the friends and pending friend requests of the authenticated user.
+func (fc *FriendsController) list(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	friends, err := fc.friends.List(ctx.Request.Context(), userID)
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching friends")
+		return
+	}
+
+	res := make([]*FriendResponse, 0, len(friends))
+	for _, f := range friends {
+		res = append(res, toResponse(f))
+	}
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// request sends a friend request.
+func (fc *FriendsController) request(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request FriendRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	friend, err := fc.friends.Request(ctx.Request.Context(), userID, request.Username)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusCreated, toResponse(friend))
+}
+
+// accept accepts a pending friend request.
+func (fc *FriendsController) accept(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	friendID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	friend, err := fc.friends.Accept(ctx.Request.Context(), userID, friendID)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, toResponse(friend))
+}
+
+// remove ends a friendship, or cancels or declines a friend request.
+func (fc *FriendsController) remove(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	friendID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	if err := fc.friends.Remove(ctx.Request.Context(), userID, friendID); err != nil {
+		response.Fail(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	res := gin.H{"message": "Friend removed successfully"}
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// toResponse maps a friend to its response representation.
+func toResponse(f *dmn.Friend) *FriendResponse {
+	return &FriendResponse{
+		UserID:   f.UserID,
+		Username: f.Username,
+		Status:   f.Status,
+		Incoming: f.Incoming,
+		Since:    f.Since,
+	}
+}
@@ -0,0 +1,22 @@
+// Package friendsapi provides structures and utilities for the friends API.
+package friendsapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FriendRequest represents a friend request to the user with the given username.
+type FriendRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// FriendResponse represents a friend or a pending friend request.
+type FriendResponse struct {
+	UserID   uuid.UUID `json:"userId"`
+	Username string    `json:"username"`
+	Status   string    `json:"status"`
+	Incoming bool      `json:"incoming"`
+	Since    time.Time `json:"since"`
+}
@@ -7,12 +7,29 @@ import (
 
 // MatchRequest represents a request to create a new game match.
 type MatchRequest struct {
-	ID     uuid.UUID `json:"id" binding:"required"`
-	SentAt int64     `json:"sent_at" binding:"required"`
+	ID     uuid.UUID `json:"id"`     // Deprecated: the caller is queued; a different ID is rejected
+	SentAt int64     `json:"sentAt"` // Deprecated: ignored; latency is measured with the ping routes
 }
 
 // MatchInfoResponse represents the response containing information about a specific match.
 type MatchInfoResponse struct {
-	SocketPubKey []byte `json:"socket_pubkey"`
-	SocketAddr   string `json:"socket_addr"`
+	SocketPubKey []byte `json:"socketPubkey"`
+	SocketAddr   string `json:"socketAddr"`
+}
+
+// PingResponse carries the nonce a client echoes back to finish a latency probe.
+type PingResponse struct {
+	Nonce string `json:"nonce"`
+}
+
+// PongResponse reports the smoothed round trip measured for the player.
+type PongResponse struct {
+	LatencyMs int64 `json:"latencyMs"`
+}
+
+// SpectateResponse represents the response for joining a match as a spectator.
+type SpectateResponse struct {
+	SocketPubKey   []byte `json:"socketPubkey"`
+	SocketAddr     string `json:"socketAddr"`
+	SpectatorToken string `json:"spectatorToken"`
 }
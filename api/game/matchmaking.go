@@ -2,9 +2,13 @@
 package gameapi
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
 	"github.com/beka-birhanu/vinom-api/service/i"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,14 +19,25 @@ type MatchMakingController struct {
 	gameSessionManager i.GameSessionManager
 	userRepo           i.UserRepo
 	matchingService    i.Matchmaker
+	spectator          i.Spectator
+	latencyProber      i.LatencyProber
+	defaultLatency     time.Duration // Used for players without a recent measurement
+	middlewares        []gin.HandlerFunc
 }
 
 // NewMatchMakingController initializes a MatchMakingController.
-func NewMatchMakingController(gsm i.GameSessionManager, ur i.UserRepo, ms i.Matchmaker) (*MatchMakingController, error) {
+// Players queued without a recent latency measurement are matched as if their
+// round trip was defaultLatency.
+// The given middlewares run before every /gameMatch route, e.g. rate limiters.
+func NewMatchMakingController(gsm i.GameSessionManager, ur i.UserRepo, ms i.Matchmaker, s i.Spectator, lp i.LatencyProber, defaultLatency time.Duration, middlewares ...gin.HandlerFunc) (*MatchMakingController, error) {
 	return &MatchMakingController{
 		gameSessionManager: gsm,
 		userRepo:           ur,
 		matchingService:    ms,
+		spectator:          s,
+		latencyProber:      lp,
+		defaultLatency:     defaultLatency,
+		middlewares:        middlewares,
 	}, nil
 }
 
@@ -31,59 +46,151 @@ func (mkc *MatchMakingController) RegisterPublic(route *gin.RouterGroup) {}
 
 // RegisterProtected registers protected routes.
 func (mkc *MatchMakingController) RegisterProtected(route *gin.RouterGroup) {
-	matchMaking := route.Group("/gameMatch")
+	matchMaking := route.Group("/gameMatch", identity.Requires("role:player", "scope:matchmaking"))
+	matchMaking.Use(mkc.middlewares...)
 	{
 		matchMaking.POST("/", mkc.match)
+		matchMaking.POST("/ping", mkc.ping)
+		matchMaking.POST("/ping/:nonce", mkc.pong)
 		matchMaking.GET("/:ID", mkc.matchInfo)
+		matchMaking.GET("/:ID/spectate", mkc.spectate)
 	}
 }
 
-// match handles match creation requests.
+// match queues the caller for a match.
 func (mkc *MatchMakingController) match(ctx *gin.Context) {
-	//TODO: match id in ctx with request
-	var request MatchRequest
-	if err := ctx.ShouldBind(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
 		return
 	}
 
-	latency := time.Now().UnixMilli() - request.SentAt
+	// The body is optional; older clients still send their own ID in it.
+	var request MatchRequest
+	if err := ctx.ShouldBind(&request); err != nil && !errors.Is(err, io.EOF) {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.ID != uuid.Nil && request.ID != userID {
+		response.Fail(ctx, http.StatusForbidden, "players can only queue themselves")
+		return
+	}
 
-	user, err := mkc.userRepo.ByID(request.ID)
+	user, err := mkc.userRepo.ByID(ctx.Request.Context(), userID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A player already in a session gets that session instead of being queued again.
+	// Lookup failures fall through to matching, since most players have no session.
+	if pubKey, socketAddr, err := mkc.gameSessionManager.SessionInfo(ctx.Request.Context(), user.ID); err == nil {
+		res := &MatchInfoResponse{
+			SocketPubKey: pubKey,
+			SocketAddr:   socketAddr,
+		}
+		response.OK(ctx, http.StatusOK, res)
 		return
 	}
 
-	err = mkc.matchingService.Match(ctx, user.ID, user.Rating, uint(latency))
+	latency, ok := mkc.latencyProber.Latency(user.ID)
+	if !ok {
+		latency = mkc.defaultLatency
+	}
+
+	err = mkc.matchingService.Match(ctx.Request.Context(), user.ID, user.Rating, uint(latency.Milliseconds()))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "error while matching player"})
+		response.FailDependency(ctx, err, http.StatusInternalServerError, "error while matching player")
 		return
 	}
 
 	ctx.Status(http.StatusAccepted)
 }
 
+// ping starts a latency probe for the caller.
+// The client finishes it by posting the returned nonce to /ping/:nonce right away.
+func (mkc *MatchMakingController) ping(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	nonce, err := mkc.latencyProber.Start(userID)
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while starting probe")
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, &PingResponse{Nonce: nonce})
+}
+
+// pong finishes the caller's latency probe and reports the measured round trip.
+func (mkc *MatchMakingController) pong(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	latency, err := mkc.latencyProber.Finish(userID, ctx.Params.ByName("nonce"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, &PongResponse{LatencyMs: latency.Milliseconds()})
+}
+
 // matchInfo retrieves information about a specific match.
 func (mkc *MatchMakingController) matchInfo(ctx *gin.Context) {
 	//TODO: match id in ctx with request
 	IDString := ctx.Params.ByName("ID")
 	ID, err := uuid.Parse(IDString)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "id not found"})
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
 		return
 	}
 
-	pubKey, socketAddr, err := mkc.gameSessionManager.SessionInfo(ctx, ID)
+	pubKey, socketAddr, err := mkc.gameSessionManager.SessionInfo(ctx.Request.Context(), ID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "No Session"})
+		response.FailDependency(ctx, err, http.StatusNotFound, "No Session")
 		return
 	}
 
-	response := &MatchInfoResponse{
+	res := &MatchInfoResponse{
 		SocketPubKey: pubKey,
 		SocketAddr:   socketAddr,
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// spectate issues a view-only token for the session of the given player.
+func (mkc *MatchMakingController) spectate(ctx *gin.Context) {
+	viewerID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	pubKey, socketAddr, token, err := mkc.spectator.Spectate(ctx.Request.Context(), viewerID, ID)
+	if err != nil {
+		response.FailDependency(ctx, err, http.StatusNotFound, "No Session")
+		return
+	}
+
+	res := &SpectateResponse{
+		SocketPubKey:   pubKey,
+		SocketAddr:     socketAddr,
+		SpectatorToken: token,
+	}
+
+	response.OK(ctx, http.StatusOK, res)
 }
@@ -0,0 +1,89 @@
+// Package healthapi serves liveness and readiness probes.
+package healthapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK   = "ok"
+	statusDown = "down"
+)
+
+// Check reports whether a dependency is usable; it must return once ctx is done.
+type Check func(ctx context.Context) error
+
+// HealthController runs dependency checks for orchestrator probes.
+type HealthController struct {
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// NewHealthController initializes a HealthController.
+// Readiness runs every check concurrently, each bounded by timeout.
+func NewHealthController(timeout time.Duration, checks map[string]Check) *HealthController {
+	return &HealthController{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (hc *HealthController) RegisterPublic(route *gin.RouterGroup) {
+	route.GET("/healthz", hc.live)
+	route.GET("/readyz", hc.ready)
+}
+
+// RegisterProtected registers protected routes.
+func (hc *HealthController) RegisterProtected(route *gin.RouterGroup) {}
+
+// live reports that the process is serving requests; it checks no dependency.
+func (hc *HealthController) live(ctx *gin.Context) {
+	response.OK(ctx, http.StatusOK, &HealthResponse{Status: statusOK})
+}
+
+// ready reports per-dependency status, failing with 503 if any check fails.
+func (hc *HealthController) ready(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), hc.timeout)
+	defer cancel()
+
+	result := &HealthResponse{
+		Status: statusOK,
+		Checks: make(map[string]*CheckResponse, len(hc.checks)),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, check := range hc.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkResult := &CheckResponse{Status: statusOK}
+			if err := check(checkCtx); err != nil {
+				checkResult = &CheckResponse{Status: statusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = checkResult
+			if checkResult.Status != statusOK {
+				result.Status = statusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if result.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+	response.OK(ctx, status, result)
+}
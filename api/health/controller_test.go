@@ -0,0 +1,63 @@
+package healthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(checks map[string]Check, path string) (int, map[string]any) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewHealthController(50*time.Millisecond, checks).RegisterPublic(&engine.RouterGroup)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	data, _ := body["data"].(map[string]any)
+	return w.Code, data
+}
+
+func TestHealthController(t *testing.T) {
+	healthy := func(context.Context) error { return nil }
+	failing := func(context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("Liveness skips dependency checks", func(t *testing.T) {
+		code, data := serve(map[string]Check{"mongo": failing}, "/healthz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]any{"status": "ok"}, data)
+	})
+
+	t.Run("Ready when every check passes", func(t *testing.T) {
+		code, data := serve(map[string]Check{"mongo": healthy, "matchmaking": healthy}, "/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ok", data["status"])
+		assert.Equal(t, map[string]any{
+			"mongo":       map[string]any{"status": "ok"},
+			"matchmaking": map[string]any{"status": "ok"},
+		}, data["checks"])
+	})
+
+	t.Run("Not ready when a check fails or times out", func(t *testing.T) {
+		code, data := serve(map[string]Check{"mongo": healthy, "matchmaking": failing, "sessionManager": hanging}, "/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "down", data["status"])
+		checks := data["checks"].(map[string]any)
+		assert.Equal(t, map[string]any{"status": "ok"}, checks["mongo"])
+		assert.Equal(t, map[string]any{"status": "down", "error": "connection refused"}, checks["matchmaking"])
+		assert.Equal(t, "down", checks["sessionManager"].(map[string]any)["status"])
+	})
+}
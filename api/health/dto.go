@@ -0,0 +1,14 @@
+// Package healthapi provides structures and utilities for the health API.
+package healthapi
+
+// HealthResponse represents the overall status and, for readiness, each dependency's status.
+type HealthResponse struct {
+	Status string                    `json:"status"`
+	Checks map[string]*CheckResponse `json:"checks,omitempty"`
+}
+
+// CheckResponse represents the status of a single dependency.
+type CheckResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
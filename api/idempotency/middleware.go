@@ -0,0 +1,141 @@
+// Package idempotency lets clients safely retry non-idempotent requests by sending
+// an Idempotency-Key header: a retried request receives the stored response of the
+// first one instead of being executed again.
+package idempotency
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyHeader carries the client chosen idempotency key.
+const KeyHeader = "Idempotency-Key"
+
+const maxKeyLength = 255
+
+// storedResponse is the response of the first request made with a key.
+// A nil body with done false marks a request that is still being served.
+type storedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+	done        bool
+	expiresAt   time.Time
+}
+
+// Keys builds middleware that serves requests carrying the same idempotency key
+// only once per caller within ttl. Safe methods such as GET pass through
+// untouched. Responses with a 5xx status are not stored, so that a retry after
+// a transient failure runs again.
+//
+// Callers are told apart by user ID, so it must run after identity.Requires has
+// admitted the caller; callers without a user ID are told apart by IP.
+func Keys(ttl time.Duration) gin.HandlerFunc {
+	var (
+		responses = make(map[string]*storedResponse)
+		nextSweep time.Time
+		mu        sync.Mutex
+	)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(KeyHeader)
+		if key == "" || isSafe(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			response.Abort(c, http.StatusBadRequest, "idempotency key too long")
+			return
+		}
+
+		caller := "ip:" + c.ClientIP()
+		if userID, err := identity.UserID(c); err == nil {
+			caller = "user:" + userID.String()
+		}
+		scopedKey := caller + " " + c.Request.Method + " " + c.FullPath() + " " + key
+
+		mu.Lock()
+		now := time.Now()
+		if now.After(nextSweep) {
+			for k, r := range responses {
+				if now.After(r.expiresAt) {
+					delete(responses, k)
+				}
+			}
+			nextSweep = now.Add(ttl)
+		}
+		stored, ok := responses[scopedKey]
+		if ok && now.After(stored.expiresAt) {
+			ok = false
+		}
+		if !ok {
+			responses[scopedKey] = &storedResponse{expiresAt: now.Add(ttl)}
+		}
+		mu.Unlock()
+
+		if ok {
+			if !stored.done {
+				response.Abort(c, http.StatusConflict, "a request with this idempotency key is in progress")
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.status, stored.contentType, stored.body)
+			c.Abort()
+			return
+		}
+
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		defer func() {
+			// Release the key if the handler panicked, so the client can retry.
+			if recovered := recover(); recovered != nil {
+				mu.Lock()
+				delete(responses, scopedKey)
+				mu.Unlock()
+				panic(recovered)
+			}
+		}()
+		c.Next()
+
+		mu.Lock()
+		defer mu.Unlock()
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			delete(responses, scopedKey)
+			return
+		}
+		responses[scopedKey] = &storedResponse{
+			status:      status,
+			contentType: recorder.Header().Get("Content-Type"),
+			body:        recorder.body.Bytes(),
+			done:        true,
+			expiresAt:   time.Now().Add(ttl),
+		}
+	}
+}
+
+func isSafe(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,69 @@
+package idempotency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	newEngine := func(status int, calls *atomic.Int32) *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		engine.POST("/match", Keys(time.Minute), func(c *gin.Context) {
+			n := calls.Add(1)
+			c.String(status, "call "+strconv.Itoa(int(n)))
+		})
+		return engine
+	}
+	post := func(engine *gin.Engine, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/match", nil)
+		if key != "" {
+			req.Header.Set(KeyHeader, key)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Replay the stored response for a repeated key", func(t *testing.T) {
+		var calls atomic.Int32
+		engine := newEngine(http.StatusAccepted, &calls)
+
+		first := post(engine, "key-1")
+		second := post(engine, "key-1")
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusAccepted, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Serve different keys and requests without a key", func(t *testing.T) {
+		var calls atomic.Int32
+		engine := newEngine(http.StatusAccepted, &calls)
+
+		post(engine, "key-1")
+		post(engine, "key-2")
+		post(engine, "")
+		post(engine, "")
+
+		assert.Equal(t, int32(4), calls.Load())
+	})
+
+	t.Run("Retry after a server error", func(t *testing.T) {
+		var calls atomic.Int32
+		engine := newEngine(http.StatusServiceUnavailable, &calls)
+
+		post(engine, "key-1")
+		post(engine, "key-1")
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
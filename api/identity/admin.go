@@ -0,0 +1,40 @@
+package identity
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokens authenticates requests bearing one of the given static tokens and
+// grants them the admin role. It replaces Authoriz on the admin listener, so that
+// admin access does not depend on the JWT secret of the public API.
+func AdminTokens(tokens []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || !matchesAny(token, tokens) {
+			response.Abort(c, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+
+		c.Set(ContextUserClaims, map[string]interface{}{
+			RolesClaim: []interface{}{dmn.RoleAdmin},
+		})
+		c.Next()
+	}
+}
+
+// matchesAny compares the token against every candidate in constant time.
+func matchesAny(token string, candidates []string) bool {
+	matched := 0
+	for _, candidate := range candidates {
+		if candidate != "" {
+			matched |= subtle.ConstantTimeCompare([]byte(token), []byte(candidate))
+		}
+	}
+	return matched == 1
+}
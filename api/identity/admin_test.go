@@ -0,0 +1,43 @@
+package identity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminTokens(t *testing.T) {
+	serve := func(authorization string) int {
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		engine.GET("/", AdminTokens([]string{"first-token", "second-token"}), Requires("role:admin"), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		engine.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Grant admin to configured tokens", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("Bearer first-token"))
+		assert.Equal(t, http.StatusOK, serve("Bearer second-token"))
+	})
+
+	t.Run("Reject other tokens", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serve("Bearer wrong-token"))
+		assert.Equal(t, http.StatusUnauthorized, serve("first-token"))
+		assert.Equal(t, http.StatusUnauthorized, serve(""))
+	})
+
+	t.Run("Never match an empty token", func(t *testing.T) {
+		assert.False(t, matchesAny("", []string{""}))
+	})
+}
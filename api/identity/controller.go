@@ -3,6 +3,7 @@ package identity
 import (
 	"net/http"
 
+	"github.com/beka-birhanu/vinom-api/api/response"
 	"github.com/beka-birhanu/vinom-api/service/i"
 	"github.com/gin-gonic/gin"
 )
@@ -10,18 +11,21 @@ import (
 // IdentityServer handles HTTP requests related to authentication.
 type IdentityServer struct {
 	authService i.Authenticator
+	middlewares []gin.HandlerFunc
 }
 
 // NewIdentityServer creates a new AuthServer.
-func NewIdentityServer(a i.Authenticator) *IdentityServer {
+// The given middlewares run before every /auth route, e.g. rate limiters.
+func NewIdentityServer(a i.Authenticator, middlewares ...gin.HandlerFunc) *IdentityServer {
 	return &IdentityServer{
 		authService: a,
+		middlewares: middlewares,
 	}
 }
 
 // RegisterPublic registers public routes.
 func (c *IdentityServer) RegisterPublic(route *gin.RouterGroup) {
-	auth := route.Group("/auth")
+	auth := route.Group("/auth", c.middlewares...)
 	{
 		auth.POST("/register", c.registerUser)
 		auth.POST("/login", c.login)
@@ -29,7 +33,16 @@ func (c *IdentityServer) RegisterPublic(route *gin.RouterGroup) {
 }
 
 // RegisterProtected registers privileged routes.
+// Only players manage an account; service tokens have none.
 func (c *IdentityServer) RegisterProtected(route *gin.RouterGroup) {
+	auth := route.Group("/auth", Requires("role:player"))
+	auth.Use(c.middlewares...)
+	{
+		auth.GET("/me", c.profile)
+		auth.PATCH("/me", c.updateProfile)
+		auth.DELETE("/me", c.deleteAccount)
+		auth.PUT("/password", c.changePassword)
+	}
 }
 
 // registerUser handles user registration.
@@ -37,18 +50,18 @@ func (c *IdentityServer) registerUser(ctx *gin.Context) {
 	var request AuthRequest
 
 	if err := ctx.ShouldBind(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err := c.authService.Register(request.Username, request.Password)
+	err := c.authService.Register(ctx.Request.Context(), request.Username, request.Password)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response := gin.H{"message": "User registered successfully"}
-	ctx.JSON(http.StatusCreated, response)
+	res := gin.H{"message": "User registered successfully"}
+	response.OK(ctx, http.StatusCreated, res)
 }
 
 // login handles user login.
@@ -56,21 +69,107 @@ func (c *IdentityServer) login(ctx *gin.Context) {
 	var request AuthRequest
 
 	if err := ctx.ShouldBind(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user, token, err := c.authService.SignIn(request.Username, request.Password)
+	user, token, err := c.authService.SignIn(ctx.Request.Context(), request.Username, request.Password)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	response := &AuthResponse{
+	res := &AuthResponse{
 		ID:       user.ID,
 		Username: user.Username,
 		Rating:   user.Rating,
 		Token:    token,
 	}
-	ctx.JSON(http.StatusOK, response)
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// profile returns the authenticated user's profile.
+func (c *IdentityServer) profile(ctx *gin.Context) {
+	userID, err := UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, err := c.authService.Profile(ctx.Request.Context(), userID)
+	if err != nil {
+		response.Fail(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, newProfileResponse(user))
+}
+
+// updateProfile changes the authenticated user's profile.
+func (c *IdentityServer) updateProfile(ctx *gin.Context) {
+	userID, err := UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request UpdateProfileRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := c.authService.UpdateProfile(ctx.Request.Context(), userID, request.Username)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, newProfileResponse(user))
+}
+
+// changePassword replaces the authenticated user's password.
+func (c *IdentityServer) changePassword(ctx *gin.Context) {
+	userID, err := UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request ChangePasswordRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.authService.ChangePassword(ctx.Request.Context(), userID, request.OldPassword, request.NewPassword); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := gin.H{"message": "Password changed successfully"}
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// deleteAccount deletes the authenticated user's account.
+func (c *IdentityServer) deleteAccount(ctx *gin.Context) {
+	userID, err := UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request DeleteAccountRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.authService.DeleteAccount(ctx.Request.Context(), userID, request.Password); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := gin.H{"message": "Account deleted successfully"}
+	response.OK(ctx, http.StatusOK, res)
 }
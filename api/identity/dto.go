@@ -1,6 +1,9 @@
 package identity
 
-import "github.com/google/uuid"
+import (
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
 
 type AuthRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -11,5 +14,34 @@ type AuthResponse struct {
 	ID       uuid.UUID `json:"id"`
 	Username string    `json:"username"`
 	Rating   int       `json:"rating"`
-	Token    string    `json:"auth_token"`
+	Token    string    `json:"authToken"`
+}
+
+type UpdateProfileRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type ProfileResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Rating   int       `json:"rating"`
+	Roles    []string  `json:"roles"`
+}
+
+func newProfileResponse(user *dmn.User) *ProfileResponse {
+	return &ProfileResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Rating:   user.Rating,
+		Roles:    user.GrantedRoles(),
+	}
 }
@@ -1,16 +1,25 @@
 package identity
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/beka-birhanu/vinom-api/api/response"
 	"github.com/beka-birhanu/vinom-api/service/i"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
 	// ContextUserClaims is the key used to store user claims in the Gin context.
 	ContextUserClaims = "userClaims"
+
+	// audienceClaim is set on tokens issued for other services, e.g. spectator
+	// tokens for the game socket. Such tokens are not API credentials.
+	audienceClaim = "aud"
+	// spectatorClaim marks view-only tokens for the game socket.
+	spectatorClaim = "spectator"
 )
 
 func Authoriz(ts i.Tokenizer) gin.HandlerFunc {
@@ -18,16 +27,14 @@ func Authoriz(ts i.Tokenizer) gin.HandlerFunc {
 		// Retrieve the access token from the Authorization header.
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.Status(http.StatusUnauthorized) // No token found in the header.
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, "missing authorization header")
 			return
 		}
 
 		// Split the "Bearer" prefix from the token.
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			c.Status(http.StatusUnauthorized) // Malformed Authorization header.
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, "malformed authorization header")
 			return
 		}
 
@@ -37,8 +44,13 @@ func Authoriz(ts i.Tokenizer) gin.HandlerFunc {
 		// Validate the token using the barrier service.
 		claims, err := ts.Decode(token)
 		if err != nil {
-			c.Status(http.StatusUnauthorized)
-			c.Abort()
+			response.Abort(c, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		_, hasAudience := claims[audienceClaim]
+		_, isSpectator := claims[spectatorClaim]
+		if hasAudience || isSpectator {
+			response.Abort(c, http.StatusUnauthorized, "invalid token")
 			return
 		}
 
@@ -47,3 +59,32 @@ func Authoriz(ts i.Tokenizer) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// UserID extracts the authenticated user's ID from the claims set by Authoriz.
+func UserID(c *gin.Context) (uuid.UUID, error) {
+	claims, err := userClaims(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	rawID, ok := claims["userID"].(string)
+	if !ok {
+		return uuid.Nil, errors.New("missing user id claim")
+	}
+
+	return uuid.Parse(rawID)
+}
+
+// userClaims returns the claims attached to the context by Authoriz.
+func userClaims(c *gin.Context) (map[string]interface{}, error) {
+	value, ok := c.Get(ContextUserClaims)
+	if !ok {
+		return nil, errors.New("missing user claims")
+	}
+
+	claims, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("malformed user claims")
+	}
+	return claims, nil
+}
@@ -0,0 +1,78 @@
+package identity
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RolesClaim is the token claim listing the roles granted to the bearer.
+	RolesClaim = "roles"
+	// ScopesClaim is the token claim listing the scopes granted to the bearer.
+	ScopesClaim = "scopes"
+)
+
+// requirement is a single claim value a route requires, e.g. role admin.
+type requirement struct {
+	claim string
+	value string
+}
+
+// Requires builds middleware that only lets through requests whose token
+// satisfies every requirement. Requirements are written "role:<name>" or
+// "scope:<name>". It must run after Authoriz.
+//
+// Malformed requirements are programming errors and cause a panic when routes are registered.
+func Requires(requirements ...string) gin.HandlerFunc {
+	parsed := make([]requirement, 0, len(requirements))
+	for _, r := range requirements {
+		kind, value, ok := strings.Cut(r, ":")
+		if !ok || value == "" {
+			panic(fmt.Sprintf("identity: malformed requirement %q", r))
+		}
+
+		switch kind {
+		case "role":
+			parsed = append(parsed, requirement{claim: RolesClaim, value: value})
+		case "scope":
+			parsed = append(parsed, requirement{claim: ScopesClaim, value: value})
+		default:
+			panic(fmt.Sprintf("identity: unknown requirement kind %q", kind))
+		}
+	}
+
+	return func(c *gin.Context) {
+		claims, err := userClaims(c)
+		if err != nil {
+			response.Abort(c, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		for _, r := range parsed {
+			if !hasClaimValue(claims, r.claim, r.value) {
+				response.Abort(c, http.StatusForbidden, "insufficient permissions")
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// hasClaimValue reports whether the list claim contains the value.
+func hasClaimValue(claims map[string]interface{}, claim, value string) bool {
+	values, ok := claims[claim].([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
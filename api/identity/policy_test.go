@@ -0,0 +1,56 @@
+package identity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithClaims(claims map[string]interface{}, requirements ...string) int {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		if claims != nil {
+			c.Set(ContextUserClaims, claims)
+		}
+		c.Next()
+	}, Requires(requirements...), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Code
+}
+
+func TestRequires(t *testing.T) {
+	claims := map[string]interface{}{
+		RolesClaim:  []interface{}{"player"},
+		ScopesClaim: []interface{}{"matchmaking"},
+	}
+
+	t.Run("Allow when every requirement is met", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serveWithClaims(claims, "role:player", "scope:matchmaking"))
+	})
+
+	t.Run("Forbid when a requirement is missing", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serveWithClaims(claims, "role:admin"))
+		assert.Equal(t, http.StatusForbidden, serveWithClaims(claims, "role:player", "scope:admin"))
+	})
+
+	t.Run("Forbid tokens without list claims", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serveWithClaims(map[string]interface{}{"spectator": true}, "role:player"))
+	})
+
+	t.Run("Reject requests without claims", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serveWithClaims(nil, "role:player"))
+	})
+
+	t.Run("Panic on malformed requirement", func(t *testing.T) {
+		assert.Panics(t, func() { Requires("admin") })
+		assert.Panics(t, func() { Requires("group:admin") })
+	})
+}
@@ -0,0 +1,169 @@
+// Package leaderboardapi exposes player rankings.
+package leaderboardapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LeaderboardController handles leaderboard queries.
+type LeaderboardController struct {
+	leaderboard i.Leaderboard
+	seasons     i.Seasons
+	middlewares []gin.HandlerFunc
+}
+
+// NewLeaderboardController initializes a LeaderboardController.
+// The given middlewares run before every /leaderboard route.
+func NewLeaderboardController(l i.Leaderboard, s i.Seasons, middlewares ...gin.HandlerFunc) *LeaderboardController {
+	return &LeaderboardController{
+		leaderboard: l,
+		seasons:     s,
+		middlewares: middlewares,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (lc *LeaderboardController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+func (lc *LeaderboardController) RegisterProtected(route *gin.RouterGroup) {
+	leaderboard := route.Group("/leaderboard", identity.Requires("role:player"))
+	leaderboard.Use(lc.middlewares...)
+	{
+		leaderboard.GET("/", lc.top)
+		leaderboard.GET("/rank/:ID", lc.rank)
+		leaderboard.GET("/around/:rank", lc.around)
+		leaderboard.GET("/season", lc.currentSeason)
+		leaderboard.GET("/seasons/:number", lc.endedSeason)
+	}
+}
+
+// top returns a page of the leaderboard.
+func (lc *LeaderboardController) top(ctx *gin.Context) {
+	var request PageRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := lc.leaderboard.Top(ctx.Request.Context(), request.Page, request.PageSize)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Paginated(ctx, http.StatusOK, toResponse(entries), request.Page, request.PageSize)
+}
+
+// rank returns the leaderboard entry of a player.
+func (lc *LeaderboardController) rank(ctx *gin.Context) {
+	ID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	entry, err := lc.leaderboard.Rank(ctx.Request.Context(), ID)
+	if err != nil {
+		response.Fail(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, toResponse([]*dmn.LeaderboardEntry{entry})[0])
+}
+
+// around returns the players surrounding a rank.
+func (lc *LeaderboardController) around(ctx *gin.Context) {
+	rank, err := strconv.Atoi(ctx.Params.ByName("rank"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "invalid rank")
+		return
+	}
+
+	var request AroundRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := lc.leaderboard.Around(ctx.Request.Context(), rank, request.Radius)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, toResponse(entries))
+}
+
+// currentSeason returns the running season.
+func (lc *LeaderboardController) currentSeason(ctx *gin.Context) {
+	season, err := lc.seasons.Current(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching season")
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, toSeasonResponse(season))
+}
+
+// endedSeason returns the final standings of an ended season.
+func (lc *LeaderboardController) endedSeason(ctx *gin.Context) {
+	number, err := strconv.Atoi(ctx.Params.ByName("number"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "invalid season number")
+		return
+	}
+
+	season, err := lc.seasons.Ended(ctx.Request.Context(), number)
+	if err != nil {
+		response.Fail(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, toSeasonResponse(season))
+}
+
+// toSeasonResponse maps a season to its response representation.
+func toSeasonResponse(season *dmn.Season) *SeasonResponse {
+	res := &SeasonResponse{
+		Number: season.Number,
+	}
+	if !season.StartedAt.IsZero() {
+		res.StartedAt = &season.StartedAt
+	}
+	if season.Ended() {
+		res.EndedAt = &season.EndedAt
+		res.Standings = make([]*EntryResponse, 0, len(season.Standings))
+		for _, s := range season.Standings {
+			res.Standings = append(res.Standings, &EntryResponse{
+				Rank:     s.Rank,
+				PlayerID: s.PlayerID,
+				Username: s.Username,
+				Rating:   s.Rating,
+			})
+		}
+	}
+	return res
+}
+
+// toResponse maps leaderboard entries to their response representation.
+func toResponse(entries []*dmn.LeaderboardEntry) []*EntryResponse {
+	res := make([]*EntryResponse, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, &EntryResponse{
+			Rank:     e.Rank,
+			PlayerID: e.PlayerID,
+			Username: e.Username,
+			Rating:   e.Rating,
+		})
+	}
+	return res
+}
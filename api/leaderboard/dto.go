@@ -0,0 +1,35 @@
+// Package leaderboardapi provides structures and utilities for leaderboard requests and responses.
+package leaderboardapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageRequest represents a paginated leaderboard query.
+type PageRequest struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"pageSize,default=10"`
+}
+
+// AroundRequest represents a query for the players around a rank.
+type AroundRequest struct {
+	Radius int `form:"radius,default=5"`
+}
+
+// EntryResponse represents a single leaderboard entry.
+type EntryResponse struct {
+	Rank     int       `json:"rank"`
+	PlayerID uuid.UUID `json:"playerId"`
+	Username string    `json:"username"`
+	Rating   int       `json:"rating"`
+}
+
+// SeasonResponse represents a ranked season; ended seasons include their final standings.
+type SeasonResponse struct {
+	Number    int              `json:"number"`
+	StartedAt *time.Time       `json:"startedAt,omitempty"`
+	EndedAt   *time.Time       `json:"endedAt,omitempty"`
+	Standings []*EntryResponse `json:"standings,omitempty"`
+}
@@ -0,0 +1,178 @@
+// Package matchapi serves the match history of players.
+package matchapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MatchHistoryController records finished matches and serves match history.
+type MatchHistoryController struct {
+	history     i.MatchHistory
+	middlewares []gin.HandlerFunc
+}
+
+// NewMatchHistoryController initializes a MatchHistoryController.
+// The given middlewares run before every player facing /matches route.
+func NewMatchHistoryController(h i.MatchHistory, middlewares ...gin.HandlerFunc) *MatchHistoryController {
+	return &MatchHistoryController{
+		history:     h,
+		middlewares: middlewares,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (mc *MatchHistoryController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+func (mc *MatchHistoryController) RegisterProtected(route *gin.RouterGroup) {
+	// Results are reported by the session manager with a service token.
+	route.POST("/matches/", identity.Requires("role:service", "scope:matches"), mc.record)
+
+	matches := route.Group("/matches", identity.Requires("role:player"))
+	matches.Use(mc.middlewares...)
+	{
+		matches.GET("/", mc.list)
+		matches.GET("/headToHead/:ID", mc.headToHead)
+	}
+}
+
+// record stores the result of a finished match and answers with the recorded
+// match, which flags the players for whom it was a placement match.
+func (mc *MatchHistoryController) record(ctx *gin.Context) {
+	var request RecordRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	match := request.toDomain()
+	if err := match.Validate(); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A retried report is answered like the first one, so the session manager can
+	// retry until it gets a 2xx.
+	recorded, err := mc.history.Record(ctx.Request.Context(), match)
+	switch {
+	case errors.Is(err, dmn.ErrMatchRecorded):
+		response.OK(ctx, http.StatusOK, toResponse([]*dmn.MatchResult{recorded})[0])
+		return
+	case err != nil:
+		response.Fail(ctx, http.StatusInternalServerError, "error while recording match")
+		return
+	}
+
+	response.OK(ctx, http.StatusCreated, toResponse([]*dmn.MatchResult{recorded})[0])
+}
+
+// list returns a page of the authenticated user's match history.
+func (mc *MatchHistoryController) list(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request PageRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matches, err := mc.history.History(ctx.Request.Context(), userID, request.Page, request.PageSize)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Paginated(ctx, http.StatusOK, toResponse(matches), request.Page, request.PageSize)
+}
+
+// headToHead returns the authenticated user's record against another player.
+func (mc *MatchHistoryController) headToHead(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	opponentID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	record, err := mc.history.HeadToHead(ctx.Request.Context(), userID, opponentID)
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.OK(ctx, http.StatusOK, &HeadToHeadResponse{
+		PlayerID:   record.PlayerID,
+		OpponentID: record.OpponentID,
+		Matches:    record.Matches,
+		Wins:       record.Wins,
+		Losses:     record.Losses,
+		Draws:      record.Draws,
+	})
+}
+
+// toDomain maps a reported match result to its domain representation.
+func (r *RecordRequest) toDomain() *dmn.MatchResult {
+	players := make([]dmn.MatchPlayer, 0, len(r.Players))
+	for _, p := range r.Players {
+		players = append(players, dmn.MatchPlayer{
+			PlayerID:     p.PlayerID,
+			Score:        p.Score,
+			RatingBefore: p.RatingBefore,
+			RatingAfter:  p.RatingAfter,
+		})
+	}
+
+	return &dmn.MatchResult{
+		ID:         r.ID,
+		Players:    players,
+		MazeWidth:  r.MazeWidth,
+		MazeHeight: r.MazeHeight,
+		StartedAt:  r.StartedAt,
+		EndedAt:    r.EndedAt,
+	}
+}
+
+// toResponse maps match results to their response representation.
+func toResponse(matches []*dmn.MatchResult) []*MatchResponse {
+	res := make([]*MatchResponse, 0, len(matches))
+	for _, m := range matches {
+		players := make([]*MatchPlayerResult, 0, len(m.Players))
+		for _, p := range m.Players {
+			players = append(players, &MatchPlayerResult{
+				PlayerID:     p.PlayerID,
+				Score:        p.Score,
+				RatingBefore: p.RatingBefore,
+				RatingAfter:  p.RatingAfter,
+				Placement:    p.Placement,
+			})
+		}
+
+		res = append(res, &MatchResponse{
+			ID:              m.ID,
+			Players:         players,
+			MazeWidth:       m.MazeWidth,
+			MazeHeight:      m.MazeHeight,
+			StartedAt:       m.StartedAt,
+			EndedAt:         m.EndedAt,
+			DurationSeconds: m.Duration().Seconds(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,54 @@
+// Package matchapi provides structures and utilities for the match history API.
+package matchapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageRequest represents a paginated match history query.
+type PageRequest struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"pageSize,default=10"`
+}
+
+// RecordRequest represents the result of a finished match reported by the session manager.
+type RecordRequest struct {
+	ID         uuid.UUID            `json:"id" binding:"required"`
+	Players    []*MatchPlayerResult `json:"players" binding:"required,min=1,dive"`
+	MazeWidth  int                  `json:"mazeWidth" binding:"required,min=1"`
+	MazeHeight int                  `json:"mazeHeight" binding:"required,min=1"`
+	StartedAt  time.Time            `json:"startedAt" binding:"required"`
+	EndedAt    time.Time            `json:"endedAt" binding:"required"`
+}
+
+// MatchPlayerResult represents a player's score and rating change in a match.
+type MatchPlayerResult struct {
+	PlayerID     uuid.UUID `json:"playerId" binding:"required"`
+	Score        int       `json:"score"`
+	RatingBefore int       `json:"ratingBefore"`
+	RatingAfter  int       `json:"ratingAfter"`
+	Placement    bool      `json:"placement"` // Ignored when reported; set from the player's placement matches
+}
+
+// MatchResponse represents a finished match in a player's history.
+type MatchResponse struct {
+	ID              uuid.UUID            `json:"id"`
+	Players         []*MatchPlayerResult `json:"players"`
+	MazeWidth       int                  `json:"mazeWidth"`
+	MazeHeight      int                  `json:"mazeHeight"`
+	StartedAt       time.Time            `json:"startedAt"`
+	EndedAt         time.Time            `json:"endedAt"`
+	DurationSeconds float64              `json:"durationSeconds"`
+}
+
+// HeadToHeadResponse represents the record of a player against an opponent.
+type HeadToHeadResponse struct {
+	PlayerID   uuid.UUID `json:"playerId"`
+	OpponentID uuid.UUID `json:"opponentId"`
+	Matches    int       `json:"matches"`
+	Wins       int       `json:"wins"`
+	Losses     int       `json:"losses"`
+	Draws      int       `json:"draws"`
+}
@@ -0,0 +1,60 @@
+// Package metricsapi instruments HTTP traffic and serves collected metrics for scraping.
+package metricsapi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/infrastruture/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsController exposes a metrics registry in the Prometheus text format.
+type MetricsController struct {
+	registry *metrics.Registry
+}
+
+// NewMetricsController initializes a MetricsController.
+func NewMetricsController(reg *metrics.Registry) *MetricsController {
+	return &MetricsController{
+		registry: reg,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (mc *MetricsController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+// Metrics are served on the admin listener only.
+func (mc *MetricsController) RegisterProtected(route *gin.RouterGroup) {
+	route.GET("/metrics", identity.Requires("role:admin"), mc.metrics)
+}
+
+// metrics renders every registered metric.
+func (mc *MetricsController) metrics(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = mc.registry.Render(ctx.Writer)
+}
+
+// Instrument records the count, latency, and concurrency of HTTP requests.
+func Instrument(reg *metrics.Registry) gin.HandlerFunc {
+	requests := reg.NewCounter("http_requests_total", "Total HTTP requests by method, route, and status.", "method", "route", "status")
+	latency := reg.NewHistogram("http_request_duration_seconds", "HTTP request latency by method and route.", metrics.DefaultBuckets, "method", "route")
+	inFlight := reg.NewGauge("http_requests_in_flight", "HTTP requests currently being served.")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		latency.Observe(time.Since(start).Seconds(), c.Request.Method, route)
+		requests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+	}
+}
@@ -0,0 +1,78 @@
+// Package ratelimit provides Gin middlewares that throttle requests.
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+)
+
+// PerIP limits requests by the client's IP address.
+func PerIP(rl i.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.Allow("ip:" + c.ClientIP()) {
+			tooManyRequests(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// PerUser limits requests by the authenticated user's ID.
+// It must run after identity.Authoriz; requests without claims fall back to the client's IP.
+func PerUser(rl i.RateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, err := identity.UserID(c); err == nil {
+			key = "user:" + userID.String()
+		}
+
+		if !rl.Allow(key) {
+			tooManyRequests(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// APIKeyHeader is the header carrying a public API key.
+const APIKeyHeader = "X-API-Key"
+
+// ByAPIKey limits requests carrying a known API key with the keyed limiter and
+// all other requests by IP with the anonymous limiter. Unknown keys are rejected.
+func ByAPIKey(apiKeys []string, keyed, anonymous i.RateLimiter) gin.HandlerFunc {
+	known := make(map[string]bool, len(apiKeys))
+	for _, k := range apiKeys {
+		known[k] = true
+	}
+
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader(APIKeyHeader)
+		if apiKey == "" {
+			if !anonymous.Allow("ip:" + c.ClientIP()) {
+				tooManyRequests(c)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if !known[apiKey] {
+			response.Abort(c, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		if !keyed.Allow("key:" + apiKey) {
+			tooManyRequests(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// tooManyRequests aborts the request with a 429 response.
+func tooManyRequests(c *gin.Context) {
+	response.Abort(c, http.StatusTooManyRequests, "too many requests")
+}
@@ -0,0 +1,183 @@
+// Package replayapi handles listing and playback of recorded matches.
+package replayapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/deadline"
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const maxPageSize = 50
+
+// ReplayController stores recorded matches and serves them for VOD playback.
+type ReplayController struct {
+	replayRepo  i.ReplayRepo
+	middlewares []gin.HandlerFunc
+}
+
+// NewReplayController initializes a ReplayController.
+// The given middlewares run before every /replays route.
+func NewReplayController(rr i.ReplayRepo, middlewares ...gin.HandlerFunc) *ReplayController {
+	return &ReplayController{
+		replayRepo:  rr,
+		middlewares: middlewares,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (rc *ReplayController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+func (rc *ReplayController) RegisterProtected(route *gin.RouterGroup) {
+	// Replays are uploaded by the session manager with a service token once a match ends.
+	route.POST("/replays/", identity.Requires("role:service", "scope:replays"), rc.save)
+
+	replays := route.Group("/replays", identity.Requires("role:player"))
+	replays.Use(rc.middlewares...)
+	{
+		replays.GET("/", rc.list)
+		replays.GET("/:ID/stream", deadline.Lift, rc.stream)
+	}
+}
+
+// save stores a recorded match and its frames. Saving a replay again replaces it,
+// so the uploader can retry until it gets a 2xx.
+func (rc *ReplayController) save(ctx *gin.Context) {
+	var request SaveRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := rc.replayRepo.Save(ctx.Request.Context(), request.toDomain()); err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while saving replay")
+		return
+	}
+
+	res := gin.H{"message": "Replay saved successfully"}
+	response.OK(ctx, http.StatusCreated, res)
+}
+
+// list returns a page of the replays of the authenticated user.
+func (rc *ReplayController) list(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var request PageRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Page < 1 {
+		response.Fail(ctx, http.StatusBadRequest, "page must be positive")
+		return
+	}
+	if request.PageSize < 1 || request.PageSize > maxPageSize {
+		response.Fail(ctx, http.StatusBadRequest, "invalid page size")
+		return
+	}
+
+	offset := (request.Page - 1) * request.PageSize
+	replays, err := rc.replayRepo.ByPlayer(ctx.Request.Context(), userID, offset, request.PageSize)
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching replays")
+		return
+	}
+
+	res := make([]*ReplaySummary, 0, len(replays))
+	for _, r := range replays {
+		res = append(res, &ReplaySummary{
+			ID:        r.ID,
+			PlayerIDs: r.PlayerIDs,
+			StartedAt: r.StartedAt,
+			EndedAt:   r.EndedAt,
+		})
+	}
+
+	response.Paginated(ctx, http.StatusOK, res, request.Page, request.PageSize)
+}
+
+// stream writes the frames of a replay the authenticated user took part in,
+// one JSON object per line.
+func (rc *ReplayController) stream(ctx *gin.Context) {
+	userID, err := identity.UserID(ctx)
+	if err != nil {
+		response.Fail(ctx, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	ID, err := uuid.Parse(ctx.Params.ByName("ID"))
+	if err != nil {
+		response.Fail(ctx, http.StatusBadRequest, "id not found")
+		return
+	}
+
+	replay, err := rc.replayRepo.ByID(ctx.Request.Context(), ID)
+	if err != nil {
+		if errors.Is(err, dmn.ErrReplayNotFound) {
+			response.Fail(ctx, http.StatusNotFound, "replay not found")
+			return
+		}
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching replay")
+		return
+	}
+	if !slices.Contains(replay.PlayerIDs, userID) {
+		response.Fail(ctx, http.StatusForbidden, "replay is not yours")
+		return
+	}
+
+	ctx.Header("Content-Type", "application/x-ndjson")
+	ctx.Status(http.StatusOK)
+
+	// Headers are sent by now, so a failure mid-stream can only cut the response short.
+	encoder := json.NewEncoder(ctx.Writer)
+	_ = rc.replayRepo.EachFrame(ctx.Request.Context(), replay.ID, func(frame *dmn.ReplayFrame) error {
+		err := encoder.Encode(&FrameResponse{
+			Version:  frame.Version,
+			At:       frame.At.UnixMilli(),
+			PlayerID: frame.PlayerID,
+			Action:   frame.Action,
+			State:    frame.State,
+		})
+		if err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+		return nil
+	})
+}
+
+// toDomain maps an uploaded replay to its domain representation.
+func (r *SaveRequest) toDomain() *dmn.Replay {
+	frames := make([]dmn.ReplayFrame, 0, len(r.Frames))
+	for _, f := range r.Frames {
+		frames = append(frames, dmn.ReplayFrame{
+			Version:  f.Version,
+			At:       time.UnixMilli(f.At),
+			PlayerID: f.PlayerID,
+			Action:   f.Action,
+			State:    f.State,
+		})
+	}
+
+	return &dmn.Replay{
+		ID:        r.ID,
+		PlayerIDs: r.PlayerIDs,
+		StartedAt: r.StartedAt,
+		EndedAt:   r.EndedAt,
+		Frames:    frames,
+	}
+}
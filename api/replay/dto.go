@@ -0,0 +1,49 @@
+// Package replayapi provides structures and utilities for browsing and streaming match replays.
+package replayapi
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageRequest represents a paginated replay list query.
+type PageRequest struct {
+	Page     int `form:"page,default=1"`
+	PageSize int `form:"pageSize,default=10"`
+}
+
+// SaveRequest represents a recorded match uploaded by the session manager.
+type SaveRequest struct {
+	ID        uuid.UUID       `json:"id" binding:"required"`
+	PlayerIDs []uuid.UUID     `json:"playerIds" binding:"required,min=1"`
+	StartedAt time.Time       `json:"startedAt" binding:"required"`
+	EndedAt   time.Time       `json:"endedAt" binding:"required"`
+	Frames    []*FrameRequest `json:"frames" binding:"dive,required"`
+}
+
+// FrameRequest represents a single uploaded replay frame, in the shape frames are streamed back.
+type FrameRequest struct {
+	Version  int64     `json:"version"`
+	At       int64     `json:"at"` // Unix milliseconds
+	PlayerID uuid.UUID `json:"playerId"`
+	Action   []byte    `json:"action,omitempty"`
+	State    []byte    `json:"state"`
+}
+
+// ReplaySummary represents a replay entry in a player's replay list.
+type ReplaySummary struct {
+	ID        uuid.UUID   `json:"id"`
+	PlayerIDs []uuid.UUID `json:"playerIds"`
+	StartedAt time.Time   `json:"startedAt"`
+	EndedAt   time.Time   `json:"endedAt"`
+}
+
+// FrameResponse represents a single replay frame sent while streaming.
+type FrameResponse struct {
+	Version  int64     `json:"version"`
+	At       int64     `json:"at"`
+	PlayerID uuid.UUID `json:"playerId"`
+	Action   []byte    `json:"action,omitempty"`
+	State    []byte    `json:"state"`
+}
@@ -0,0 +1,157 @@
+package response
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// toSnake converts a camelCase key to snake_case, e.g. socketAddr to socket_addr.
+func toSnake(key string) string {
+	var sb strings.Builder
+	for idx, r := range key {
+		if unicode.IsUpper(r) {
+			if idx > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// toCamel converts a snake_case key to camelCase, e.g. page_size to pageSize.
+func toCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for idx := 1; idx < len(parts); idx++ {
+		if parts[idx] != "" {
+			parts[idx] = strings.ToUpper(parts[idx][:1]) + parts[idx][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// convertFields renames the object keys of a decoded JSON value that were encoded
+// from the fields of a struct in value. Keys of maps are data, not field names, and
+// are left untouched, as is anything value has no struct for.
+func convertFields(value reflect.Value, decoded any, convert func(string) string) any {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		value = value.Elem()
+	}
+
+	switch v := decoded.(type) {
+	case map[string]any:
+		var children map[string]reflect.Value
+		rename := convert
+		switch {
+		case !value.IsValid():
+			return v
+		case value.Kind() == reflect.Struct:
+			children = make(map[string]reflect.Value)
+			structFields(value, children)
+		case value.Kind() == reflect.Map:
+			children = make(map[string]reflect.Value, value.Len())
+			for iter := value.MapRange(); iter.Next(); {
+				children[mapKey(iter.Key())] = iter.Value()
+			}
+			rename = func(key string) string { return key }
+		default:
+			return v
+		}
+
+		converted := make(map[string]any, len(v))
+		for key, inner := range v {
+			child, ok := children[key]
+			if !ok {
+				converted[key] = inner
+				continue
+			}
+			converted[rename(key)] = convertFields(child, inner, convert)
+		}
+		return converted
+	case []any:
+		if !value.IsValid() || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+			return v
+		}
+		for idx, inner := range v {
+			if idx < value.Len() {
+				v[idx] = convertFields(value.Index(idx), inner, convert)
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// structFields maps the JSON names of the fields of a struct to their values,
+// promoting the fields of embedded structs like encoding/json does.
+func structFields(value reflect.Value, fields map[string]reflect.Value) {
+	for idx := 0; idx < value.NumField(); idx++ {
+		field := value.Type().Field(idx)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldValue := value.Field(idx)
+		if field.Anonymous && name == "" {
+			for fieldValue.Kind() == reflect.Pointer && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Struct {
+				structFields(fieldValue, fields)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := fields[name]; !ok {
+			fields[name] = fieldValue
+		}
+	}
+}
+
+// mapKey returns the JSON object key encoding/json writes for a map key.
+func mapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, _ := marshaler.MarshalText()
+		return string(text)
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	}
+	return ""
+}
+
+// convertKeys renames every object key in a decoded JSON value.
+func convertKeys(value any, convert func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, inner := range v {
+			converted[convert(key)] = convertKeys(inner, convert)
+		}
+		return converted
+	case []any:
+		for idx, inner := range v {
+			v[idx] = convertKeys(inner, convert)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,130 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/infrastruture/correlation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the request ID in requests and responses.
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+
+	contextRequestID   = "requestID"
+	contextLegacy      = "legacyResponses"
+	contextConsistency = "readConsistency"
+)
+
+// Middleware assigns every request an ID and selects the response shape.
+// An inbound X-Request-ID is kept only if it is at most 128 letters, digits
+// and '-', '_', '.' or ':'; otherwise a new ID replaces it, since the ID is
+// echoed into responses, logs and gRPC metadata.
+// With legacy enabled, snake_case JSON body and query keys are also renamed
+// to camelCase so old clients keep binding to the new DTOs.
+func Middleware(legacy bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(contextRequestID, requestID)
+		c.Request = c.Request.WithContext(correlation.WithID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		if legacy {
+			c.Set(contextLegacy, true)
+			camelCaseRequest(c)
+		}
+		c.Next()
+	}
+}
+
+// validRequestID reports whether an inbound request ID is safe to propagate.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID returns the ID assigned to the request by Middleware.
+func RequestID(c *gin.Context) string {
+	return c.GetString(contextRequestID)
+}
+
+// IsLegacy reports whether the request should be answered with the legacy shapes.
+func IsLegacy(c *gin.Context) bool {
+	return c.GetBool(contextLegacy)
+}
+
+// ReadConsistency documents in the meta of every response that the route may read
+// from replicas with the given read preference and maximum staleness.
+// It does nothing for the primary read preference, whose reads are never stale.
+func ReadConsistency(readPreference string, maxStaleness time.Duration) gin.HandlerFunc {
+	if readPreference == "" || readPreference == "primary" {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	consistency := &Consistency{
+		ReadPreference:      readPreference,
+		MaxStalenessSeconds: int(maxStaleness.Seconds()),
+	}
+	return func(c *gin.Context) {
+		c.Set(contextConsistency, consistency)
+		c.Next()
+	}
+}
+
+func consistency(c *gin.Context) *Consistency {
+	value, _ := c.Get(contextConsistency)
+	consistency, _ := value.(*Consistency)
+	return consistency
+}
+
+// camelCaseRequest renames snake_case query parameters and JSON body keys.
+// The target DTO is unknown here, so every body key is renamed; request DTOs
+// therefore must not take map-typed fields, whose keys are user data.
+func camelCaseRequest(c *gin.Context) {
+	query := c.Request.URL.Query()
+	for key, values := range query {
+		if camel := toCamel(key); camel != key {
+			query.Del(key)
+			query[camel] = values
+		}
+	}
+	c.Request.URL.RawQuery = query.Encode()
+
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), gin.MIMEJSON) {
+		return
+	}
+
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err == nil {
+		if converted, err := json.Marshal(convertKeys(decoded, toCamel)); err == nil {
+			raw = converted
+		}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+	c.Request.ContentLength = int64(len(raw))
+}
@@ -0,0 +1,122 @@
+// Package response writes the standard JSON envelope shared by all controllers:
+//
+//	{"data": ..., "error": {"message": ...}, "meta": {"requestId": ..., "pagination": ...}}
+//
+// When legacy mode is enabled (see Middleware), the pre-envelope shapes are written
+// instead: bare data with snake_case keys and {"error": "..."} on failure.
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+)
+
+// Envelope is the body of every JSON response.
+type Envelope struct {
+	Data  any    `json:"data,omitempty"`
+	Error *Error `json:"error,omitempty"`
+	Meta  Meta   `json:"meta"`
+}
+
+// Error describes why a request failed.
+type Error struct {
+	Message string `json:"message"`
+}
+
+// Meta carries information about the response itself.
+type Meta struct {
+	RequestID   string       `json:"requestId,omitempty"`
+	Pagination  *Pagination  `json:"pagination,omitempty"`
+	Consistency *Consistency `json:"consistency,omitempty"`
+}
+
+// Pagination describes the page returned by a paginated endpoint.
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+}
+
+// Consistency describes how stale the data of a response served from a replica may be.
+type Consistency struct {
+	ReadPreference      string `json:"readPreference"`
+	MaxStalenessSeconds int    `json:"maxStalenessSeconds,omitempty"`
+}
+
+// OK writes data with the given status.
+func OK(ctx *gin.Context, status int, data any) {
+	write(ctx, status, &Envelope{Data: data})
+}
+
+// Paginated writes a page of data with its pagination metadata.
+func Paginated(ctx *gin.Context, status int, data any, page, pageSize int) {
+	write(ctx, status, &Envelope{
+		Data: data,
+		Meta: Meta{Pagination: &Pagination{Page: page, PageSize: pageSize}},
+	})
+}
+
+// Fail writes an error message with the given status.
+func Fail(ctx *gin.Context, status int, message string) {
+	write(ctx, status, &Envelope{Error: &Error{Message: message}})
+}
+
+// FailDependency writes 503 with a Retry-After header when err reports a temporarily
+// unavailable dependency, and the given status and message otherwise.
+func FailDependency(ctx *gin.Context, err error, status int, message string) {
+	var unavailable i.Unavailable
+	if errors.As(err, &unavailable) {
+		seconds := int(math.Ceil(unavailable.RetryAfter().Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(max(seconds, 1)))
+		Fail(ctx, http.StatusServiceUnavailable, "service temporarily unavailable")
+		return
+	}
+	Fail(ctx, status, message)
+}
+
+// Abort writes an error message with the given status and stops the handler chain.
+func Abort(ctx *gin.Context, status int, message string) {
+	Fail(ctx, status, message)
+	ctx.Abort()
+}
+
+func write(ctx *gin.Context, status int, envelope *Envelope) {
+	if !IsLegacy(ctx) {
+		envelope.Meta.RequestID = RequestID(ctx)
+		envelope.Meta.Consistency = consistency(ctx)
+		ctx.JSON(status, envelope)
+		return
+	}
+
+	if envelope.Error != nil {
+		ctx.JSON(status, gin.H{"error": envelope.Error.Message})
+		return
+	}
+
+	legacy, err := legacyBody(envelope.Data)
+	if err != nil {
+		ctx.JSON(status, envelope.Data)
+		return
+	}
+	ctx.JSON(status, legacy)
+}
+
+// legacyBody re-encodes data with snake_case field names.
+func legacyBody(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+	return convertFields(reflect.ValueOf(data), decoded, toSnake), nil
+}
@@ -0,0 +1,176 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/infrastruture/correlation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type sample struct {
+	SocketAddr string `json:"socketAddr"`
+	SentAt     int64  `json:"sentAt"`
+}
+
+func newEngine(legacy bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Middleware(legacy))
+	engine.POST("/echo", func(ctx *gin.Context) {
+		var request sample
+		if err := ctx.ShouldBindJSON(&request); err != nil {
+			Fail(ctx, http.StatusBadRequest, "bad request")
+			return
+		}
+		OK(ctx, http.StatusOK, &request)
+	})
+	return engine
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Run("Wrap data with request id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"socketAddr":"a:1","sentAt":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(RequestIDHeader, "req-1")
+		newEngine(false).ServeHTTP(w, req)
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, map[string]any{"socketAddr": "a:1", "sentAt": float64(5)}, body["data"])
+		assert.Equal(t, map[string]any{"requestId": "req-1"}, body["meta"])
+		assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("Carry the request id on the request context", func(t *testing.T) {
+		var id string
+		engine := newEngine(false)
+		engine.GET("/id", func(ctx *gin.Context) {
+			id = correlation.ID(ctx.Request.Context())
+		})
+		req := httptest.NewRequest(http.MethodGet, "/id", nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "req-1", id)
+	})
+
+	t.Run("Replace malformed request ids", func(t *testing.T) {
+		for _, id := range []string{"req 1\r\nX-Evil: 1", "<script>", strings.Repeat("a", 129)} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header[RequestIDHeader] = []string{id}
+			newEngine(false).ServeHTTP(w, req)
+
+			assert.NotEqual(t, id, w.Header().Get(RequestIDHeader))
+			assert.NoError(t, uuid.Validate(w.Header().Get(RequestIDHeader)))
+		}
+	})
+
+	t.Run("Wrap error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`not json`))
+		req.Header.Set("Content-Type", "application/json")
+		newEngine(false).ServeHTTP(w, req)
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, map[string]any{"message": "bad request"}, body["error"])
+	})
+
+	t.Run("Legacy mode keeps snake_case shapes", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"socket_addr":"a:1","sent_at":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		newEngine(true).ServeHTTP(w, req)
+
+		assert.JSONEq(t, `{"socket_addr":"a:1","sent_at":5}`, w.Body.String())
+	})
+
+	t.Run("Legacy mode error shape", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`not json`))
+		req.Header.Set("Content-Type", "application/json")
+		newEngine(true).ServeHTTP(w, req)
+
+		assert.JSONEq(t, `{"error":"bad request"}`, w.Body.String())
+	})
+}
+
+func TestCasing(t *testing.T) {
+	assert.Equal(t, "socket_pubkey", toSnake("socketPubkey"))
+	assert.Equal(t, "auth_token", toSnake("authToken"))
+	assert.Equal(t, "id", toSnake("id"))
+	assert.Equal(t, "pageSize", toCamel("page_size"))
+	assert.Equal(t, "id", toCamel("id"))
+}
+
+func TestLegacyBody(t *testing.T) {
+	type player struct {
+		PlayerName string `json:"playerName"`
+	}
+	type inner struct {
+		TotalScore int `json:"totalScore"`
+	}
+	data := struct {
+		inner
+		Modifiers map[string]float64 `json:"modifiers"`
+		Players   map[string]*player `json:"players"`
+		List      []player           `json:"list"`
+		Raw       any                `json:"raw"`
+	}{
+		inner:     inner{TotalScore: 3},
+		Modifiers: map[string]float64{"speedBoost": 1.5},
+		Players:   map[string]*player{"teamA": {PlayerName: "p1"}},
+		List:      []player{{PlayerName: "p2"}},
+		Raw:       map[string]any{"userKey": player{PlayerName: "p3"}},
+	}
+
+	body, err := legacyBody(&data)
+	assert.NoError(t, err)
+	raw, _ := json.Marshal(body)
+	assert.JSONEq(t, `{
+		"total_score": 3,
+		"modifiers": {"speedBoost": 1.5},
+		"players": {"teamA": {"player_name": "p1"}},
+		"list": [{"player_name": "p2"}],
+		"raw": {"userKey": {"player_name": "p3"}}
+	}`, string(raw))
+}
+
+func TestReadConsistency(t *testing.T) {
+	serve := func(middleware gin.HandlerFunc) map[string]any {
+		gin.SetMode(gin.TestMode)
+		engine := gin.New()
+		engine.Use(Middleware(false), middleware)
+		engine.GET("/", func(ctx *gin.Context) { OK(ctx, http.StatusOK, "ok") })
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		engine.ServeHTTP(w, req)
+
+		var body map[string]any
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return body["meta"].(map[string]any)
+	}
+
+	t.Run("Report replica staleness bound", func(t *testing.T) {
+		meta := serve(ReadConsistency("secondaryPreferred", 90*time.Second))
+		assert.Equal(t, map[string]any{"readPreference": "secondaryPreferred", "maxStalenessSeconds": float64(90)}, meta["consistency"])
+	})
+
+	t.Run("Omit for primary reads", func(t *testing.T) {
+		meta := serve(ReadConsistency("primary", 0))
+		assert.Equal(t, map[string]any{"requestId": "req-1"}, meta)
+	})
+}
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/beka-birhanu/vinom-api/api/i"
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +15,8 @@ type Router struct {
 	baseURL                 string
 	controllers             []i.Controller
 	authorizationMiddleware gin.HandlerFunc
+	middlewares             []gin.HandlerFunc
+	trustedProxies          []string
 }
 
 // Config holds configuration settings for creating a new Router instance.
@@ -20,6 +25,8 @@ type Config struct {
 	BaseURL                 string // Base URL for API routes
 	Controllers             []i.Controller
 	AuthorizationMiddleware gin.HandlerFunc
+	Middlewares             []gin.HandlerFunc // Applied to every route, before authorization
+	TrustedProxies          []string          // IPs or CIDRs whose X-Forwarded-For is trusted; empty trusts none
 }
 
 // NewRouter creates a new Router instance with the given configuration.
@@ -30,18 +37,37 @@ func NewRouter(config Config) *Router {
 		baseURL:                 config.BaseURL,
 		controllers:             config.Controllers,
 		authorizationMiddleware: config.AuthorizationMiddleware,
+		middlewares:             config.Middlewares,
+		trustedProxies:          config.TrustedProxies,
 	}
 }
 
-// Run starts the HTTP server and sets up routes with different access levels.
+// Run starts the HTTP server on the configured address and blocks while it serves.
+func (r *Router) Run() error {
+	return http.ListenAndServe(r.addr, r.Handler())
+}
+
+// Addr returns the address the router is configured to listen on.
+func (r *Router) Addr() string {
+	return r.addr
+}
+
+// Handler builds the HTTP handler and sets up routes with different access levels.
 //
 // Routes are grouped and managed under the base URL, with the following access levels:
 // - Public routes: No authentication required.
-// - Protected routes: Authentication required.
-func (r *Router) Run() error {
+// - Protected routes: Authentication required, plus the roles/scopes declared with identity.Requires.
+func (r *Router) Handler() http.Handler {
 	gin.ForceConsoleColor()
 	router := gin.Default()
 
+	// The client IP keys the rate limiters, so forwarded headers are only believed
+	// from configured proxies. Config validation rejects malformed entries.
+	if err := router.SetTrustedProxies(r.trustedProxies); err != nil {
+		panic(fmt.Sprintf("api: invalid trusted proxies: %v", err))
+	}
+	router.Use(r.middlewares...)
+
 	// Setting up routes under baseURL
 	api := router.Group(r.baseURL)
 
@@ -64,5 +90,5 @@ func (r *Router) Run() error {
 		}
 	}
 
-	return router.Run(r.addr)
+	return router
 }
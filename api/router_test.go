@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beka-birhanu/vinom-api/api/i"
+	"github.com/beka-birhanu/vinom-api/api/ratelimit"
+	infra_ratelimit "github.com/beka-birhanu/vinom-api/infrastruture/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// pingController serves GET /ping behind the given middleware.
+type pingController struct {
+	middleware gin.HandlerFunc
+}
+
+func (p pingController) RegisterPublic(route *gin.RouterGroup) {
+	route.GET("/ping", p.middleware, func(c *gin.Context) { c.Status(http.StatusOK) })
+}
+
+func (p pingController) RegisterProtected(route *gin.RouterGroup) {}
+
+func TestRouterClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newHandler := func(trustedProxies []string) http.Handler {
+		limiter := infra_ratelimit.NewTokenBucket(0.001, 1)
+		return NewRouter(Config{
+			BaseURL:                 "/api",
+			Controllers:             []i.Controller{pingController{middleware: ratelimit.PerIP(limiter)}},
+			AuthorizationMiddleware: func(c *gin.Context) { c.Next() },
+			TrustedProxies:          trustedProxies,
+		}).Handler()
+	}
+	serve := func(handler http.Handler, forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("Ignore forwarded headers by default", func(t *testing.T) {
+		handler := newHandler(nil)
+		assert.Equal(t, http.StatusOK, serve(handler, "203.0.113.1"))
+		assert.Equal(t, http.StatusTooManyRequests, serve(handler, "203.0.113.2"))
+	})
+
+	t.Run("Believe forwarded headers from trusted proxies", func(t *testing.T) {
+		handler := newHandler([]string{"192.0.2.0/24"})
+		assert.Equal(t, http.StatusOK, serve(handler, "203.0.113.1"))
+		assert.Equal(t, http.StatusOK, serve(handler, "203.0.113.2"))
+		assert.Equal(t, http.StatusTooManyRequests, serve(handler, "203.0.113.1"))
+	})
+}
@@ -0,0 +1,75 @@
+package statsapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cache holds a value loaded by load for ttl. Stale values are served while one
+// refresh runs in the background, detached from the request that started it, so
+// a slow or failing load neither blocks other callers nor is canceled by a
+// client disconnecting.
+type cache[T any] struct {
+	load    func(ctx context.Context) (*T, error)
+	ttl     time.Duration
+	timeout time.Duration // Bounds a single load
+
+	mu        sync.Mutex
+	value     *T
+	updatedAt time.Time
+	err       error         // Error of the last load
+	done      chan struct{} // Closed when the running load finishes; nil when none runs
+}
+
+// get returns the cached value, starting a refresh when it is stale. Only callers
+// that find no value at all wait for the refresh, as long as ctx allows.
+func (c *cache[T]) get(ctx context.Context) (*T, error) {
+	c.mu.Lock()
+	if c.value != nil && time.Since(c.updatedAt) <= c.ttl {
+		value := c.value
+		c.mu.Unlock()
+		return value, nil
+	}
+	if c.done == nil {
+		c.done = make(chan struct{})
+		go c.refresh(context.WithoutCancel(ctx), c.done)
+	}
+	value, done := c.value, c.done
+	c.mu.Unlock()
+
+	if value != nil {
+		return value, nil
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.value == nil {
+		return nil, c.err
+	}
+	return c.value, nil
+}
+
+// refresh loads a new value and, on success, replaces the cached one.
+func (c *cache[T]) refresh(ctx context.Context, done chan struct{}) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	value, err := c.load(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.value = value
+		c.updatedAt = time.Now()
+	}
+	c.err = err
+	c.done = nil
+	close(done)
+}
@@ -0,0 +1,56 @@
+package statsapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("Serve the stale value when a refresh fails", func(t *testing.T) {
+		calls := 0
+		c := &cache[int]{ttl: time.Millisecond, timeout: time.Second, load: func(context.Context) (*int, error) {
+			calls++
+			if calls > 1 {
+				return nil, errors.New("unexpected error: connection reset")
+			}
+			value := calls
+			return &value, nil
+		}}
+
+		value, err := c.get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *value)
+
+		time.Sleep(2 * time.Millisecond)
+		value, err = c.get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *value)
+	})
+
+	t.Run("Finish a refresh after its caller gives up", func(t *testing.T) {
+		loaded := make(chan error, 1)
+		release := make(chan struct{})
+		c := &cache[int]{ttl: time.Minute, timeout: time.Second, load: func(ctx context.Context) (*int, error) {
+			<-release
+			loaded <- ctx.Err()
+			value := 7
+			return &value, nil
+		}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.get(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		close(release)
+		assert.NoError(t, <-loaded)
+		assert.Eventually(t, func() bool {
+			value, err := c.get(context.Background())
+			return err == nil && *value == 7
+		}, time.Second, time.Millisecond)
+	})
+}
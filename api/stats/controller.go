@@ -0,0 +1,112 @@
+// Package statsapi exposes cached, read-only stats for community sites.
+package statsapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	topPlayersCount = 10
+	cacheTTL        = 30 * time.Second
+	refreshTimeout  = 5 * time.Second
+)
+
+// PublicStatsController serves unauthenticated stats from a short-lived cache.
+type PublicStatsController struct {
+	leaderboard      i.Leaderboard
+	history          i.MatchHistory
+	middlewares      []gin.HandlerFunc
+	leaderboardCache *cache[LeaderboardResponse]
+	gamesCache       *cache[GamesTodayResponse]
+}
+
+// NewPublicStatsController initializes a PublicStatsController.
+// The given middlewares run before every /public route, e.g. rate limiters.
+func NewPublicStatsController(l i.Leaderboard, h i.MatchHistory, middlewares ...gin.HandlerFunc) *PublicStatsController {
+	sc := &PublicStatsController{
+		leaderboard: l,
+		history:     h,
+		middlewares: middlewares,
+	}
+	sc.leaderboardCache = &cache[LeaderboardResponse]{load: sc.loadTopPlayers, ttl: cacheTTL, timeout: refreshTimeout}
+	sc.gamesCache = &cache[GamesTodayResponse]{load: sc.loadGamesToday, ttl: cacheTTL, timeout: refreshTimeout}
+	return sc
+}
+
+// RegisterPublic registers public routes.
+func (sc *PublicStatsController) RegisterPublic(route *gin.RouterGroup) {
+	public := route.Group("/public", sc.middlewares...)
+	{
+		public.GET("/leaderboard", sc.topPlayers)
+		public.GET("/games/today", sc.gamesToday)
+	}
+}
+
+// RegisterProtected registers protected routes.
+func (sc *PublicStatsController) RegisterProtected(route *gin.RouterGroup) {}
+
+// topPlayers returns the top players from the cache.
+func (sc *PublicStatsController) topPlayers(ctx *gin.Context) {
+	res, err := sc.leaderboardCache.get(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while fetching leaderboard")
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=30")
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// gamesToday returns the number of matches played today from the cache.
+func (sc *PublicStatsController) gamesToday(ctx *gin.Context) {
+	res, err := sc.gamesCache.get(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while counting games")
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=30")
+	response.OK(ctx, http.StatusOK, res)
+}
+
+// loadTopPlayers fetches the top players from the leaderboard.
+func (sc *PublicStatsController) loadTopPlayers(ctx context.Context) (*LeaderboardResponse, error) {
+	entries, err := sc.leaderboard.Top(ctx, 1, topPlayersCount)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]*TopPlayerResponse, 0, len(entries))
+	for _, e := range entries {
+		players = append(players, &TopPlayerResponse{
+			Rank:     e.Rank,
+			Username: e.Username,
+			Rating:   e.Rating,
+		})
+	}
+	return &LeaderboardResponse{
+		Players:   players,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// loadGamesToday counts the matches played since midnight UTC.
+func (sc *PublicStatsController) loadGamesToday(ctx context.Context) (*GamesTodayResponse, error) {
+	games, err := sc.history.PlayedToday(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &GamesTodayResponse{
+		Date:      now.UTC().Format(time.DateOnly),
+		Games:     games,
+		UpdatedAt: now,
+	}, nil
+}
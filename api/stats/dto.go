@@ -0,0 +1,25 @@
+// Package statsapi provides structures and utilities for the public stats API.
+package statsapi
+
+import "time"
+
+// TopPlayerResponse represents a player on the public leaderboard.
+// Player IDs are deliberately left out.
+type TopPlayerResponse struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Rating   int    `json:"rating"`
+}
+
+// LeaderboardResponse represents the cached public leaderboard.
+type LeaderboardResponse struct {
+	Players   []*TopPlayerResponse `json:"players"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// GamesTodayResponse represents the cached number of matches played today.
+type GamesTodayResponse struct {
+	Date      string    `json:"date"` // UTC day the games were counted for, as YYYY-MM-DD
+	Games     int64     `json:"games"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,93 @@
+// Package usersapi lets operators browse user accounts.
+package usersapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxPage     = 10000 // Keeps (page-1)*pageSize far from overflowing and skips bounded
+	maxPageSize = 100
+	listTimeout = 5 * time.Second
+)
+
+// UsersController serves user listings on the admin listener.
+type UsersController struct {
+	userRepo    i.UserRepo
+	middlewares []gin.HandlerFunc
+}
+
+// NewUsersController initializes a UsersController.
+// The given middlewares run before every /users route.
+func NewUsersController(ur i.UserRepo, middlewares ...gin.HandlerFunc) *UsersController {
+	return &UsersController{
+		userRepo:    ur,
+		middlewares: middlewares,
+	}
+}
+
+// RegisterPublic registers public routes.
+func (uc *UsersController) RegisterPublic(route *gin.RouterGroup) {}
+
+// RegisterProtected registers protected routes.
+func (uc *UsersController) RegisterProtected(route *gin.RouterGroup) {
+	users := route.Group("/users", identity.Requires("role:admin"))
+	users.Use(uc.middlewares...)
+	{
+		users.GET("/", uc.list)
+	}
+}
+
+// list returns a page of users filtered by rating range and username prefix.
+func (uc *UsersController) list(ctx *gin.Context) {
+	var request ListRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		response.Fail(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sort := dmn.UserSort(request.Sort)
+	if !sort.Valid() {
+		response.Fail(ctx, http.StatusBadRequest, "sort must be one of -rating, rating, username")
+		return
+	}
+	if request.Page < 1 || request.Page > maxPage || request.PageSize < 1 || request.PageSize > maxPageSize {
+		response.Fail(ctx, http.StatusBadRequest, "invalid page")
+		return
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx.Request.Context(), listTimeout)
+	defer cancel()
+
+	filter := dmn.UserFilter{
+		MinRating:      request.MinRating,
+		MaxRating:      request.MaxRating,
+		UsernamePrefix: request.Username,
+	}
+	users, err := uc.userRepo.List(listCtx, filter, sort, request.Page, request.PageSize)
+	if err != nil {
+		response.Fail(ctx, http.StatusInternalServerError, "error while listing users")
+		return
+	}
+
+	res := make([]*UserResponse, 0, len(users))
+	for _, u := range users {
+		res = append(res, &UserResponse{
+			ID:                   u.ID,
+			Username:             u.Username,
+			Rating:               u.Rating,
+			Roles:                u.GrantedRoles(),
+			PlacementMatchesLeft: u.PlacementMatchesLeft,
+		})
+	}
+
+	response.Paginated(ctx, http.StatusOK, res, request.Page, request.PageSize)
+}
@@ -0,0 +1,70 @@
+package usersapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := repotest.NewInMemoryUserRepo()
+	for username, rating := range map[string]int{"abebe": 1500, "abel": 1300, "bekele": 1600, "abdi": 1450} {
+		assert.NoError(t, users.Save(context.Background(), &dmn.User{ID: uuid.New(), Username: username, Rating: rating, PasswordHash: "hash"}))
+	}
+
+	engine := gin.New()
+	engine.Use(response.Middleware(false))
+	uc := NewUsersController(users)
+	uc.RegisterProtected(engine.Group("/", identity.AdminTokens([]string{"token"})))
+
+	serve := func(query string) (int, []map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, "/users/?"+query, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		var res struct {
+			Data []map[string]any `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		return w.Code, res.Data
+	}
+
+	t.Run("Filter by prefix and rating range", func(t *testing.T) {
+		code, data := serve("username=ab&minRating=1400&sort=username")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, data, 2)
+		assert.Equal(t, "abdi", data[0]["username"])
+		assert.Equal(t, "abebe", data[1]["username"])
+		assert.NotContains(t, data[0], "passwordHash")
+	})
+
+	t.Run("Paginate in rating order", func(t *testing.T) {
+		code, data := serve("page=2&pageSize=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Len(t, data, 2)
+		assert.Equal(t, "abdi", data[0]["username"])
+		assert.Equal(t, "abel", data[1]["username"])
+	})
+
+	t.Run("Reject unknown sort", func(t *testing.T) {
+		code, _ := serve("sort=password")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Reject pages past the cap", func(t *testing.T) {
+		code, _ := serve("page=9223372036854775807&pageSize=100")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -0,0 +1,24 @@
+package usersapi
+
+import (
+	"github.com/google/uuid"
+)
+
+// ListRequest represents a filtered, paginated query of users.
+type ListRequest struct {
+	MinRating *int   `form:"minRating"`
+	MaxRating *int   `form:"maxRating"`
+	Username  string `form:"username"` // Username prefix
+	Sort      string `form:"sort,default=-rating"`
+	Page      int    `form:"page,default=1"`
+	PageSize  int    `form:"pageSize,default=20"`
+}
+
+// UserResponse represents a user as seen by operators.
+type UserResponse struct {
+	ID                   uuid.UUID `json:"id"`
+	Username             string    `json:"username"`
+	Rating               int       `json:"rating"`
+	Roles                []string  `json:"roles"`
+	PlacementMatchesLeft int       `json:"placementMatchesLeft"`
+}
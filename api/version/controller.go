@@ -0,0 +1,40 @@
+// Package versionapi reports which build of the API is running.
+package versionapi
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/beka-birhanu/vinom-api/api/response"
+	"github.com/gin-gonic/gin"
+)
+
+// VersionController serves the build information of the running binary.
+type VersionController struct {
+	version *VersionResponse
+}
+
+// NewVersionController initializes a VersionController.
+func NewVersionController(version, commit, buildTime string) *VersionController {
+	return &VersionController{
+		version: &VersionResponse{
+			Version:   version,
+			Commit:    commit,
+			BuildTime: buildTime,
+			GoVersion: runtime.Version(),
+		},
+	}
+}
+
+// RegisterPublic registers public routes.
+func (vc *VersionController) RegisterPublic(route *gin.RouterGroup) {
+	route.GET("/version", vc.get)
+}
+
+// RegisterProtected registers protected routes.
+func (vc *VersionController) RegisterProtected(route *gin.RouterGroup) {}
+
+// get returns the build information.
+func (vc *VersionController) get(ctx *gin.Context) {
+	response.OK(ctx, http.StatusOK, vc.version)
+}
@@ -0,0 +1,10 @@
+// Package versionapi provides structures and utilities for the version API.
+package versionapi
+
+// VersionResponse represents the build information of the running binary.
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
@@ -0,0 +1,249 @@
+// Package app wires the API's dependencies together and runs its HTTP listeners.
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/api"
+	"github.com/beka-birhanu/vinom-api/api/deadline"
+	eventapi "github.com/beka-birhanu/vinom-api/api/event"
+	friendsapi "github.com/beka-birhanu/vinom-api/api/friends"
+	gameapi "github.com/beka-birhanu/vinom-api/api/game"
+	healthapi "github.com/beka-birhanu/vinom-api/api/health"
+	api_i "github.com/beka-birhanu/vinom-api/api/i"
+	"github.com/beka-birhanu/vinom-api/api/idempotency"
+	"github.com/beka-birhanu/vinom-api/api/identity"
+	leaderboardapi "github.com/beka-birhanu/vinom-api/api/leaderboard"
+	matchapi "github.com/beka-birhanu/vinom-api/api/match"
+	metricsapi "github.com/beka-birhanu/vinom-api/api/metrics"
+	"github.com/beka-birhanu/vinom-api/api/ratelimit"
+	replayapi "github.com/beka-birhanu/vinom-api/api/replay"
+	"github.com/beka-birhanu/vinom-api/api/response"
+	statsapi "github.com/beka-birhanu/vinom-api/api/stats"
+	usersapi "github.com/beka-birhanu/vinom-api/api/users"
+	versionapi "github.com/beka-birhanu/vinom-api/api/version"
+	"github.com/beka-birhanu/vinom-api/config"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/contentfilter"
+	"github.com/beka-birhanu/vinom-api/infrastruture/latency"
+	"github.com/beka-birhanu/vinom-api/infrastruture/metrics"
+	infra_ratelimit "github.com/beka-birhanu/vinom-api/infrastruture/ratelimit"
+	"github.com/beka-birhanu/vinom-api/infrastruture/token"
+	"github.com/beka-birhanu/vinom-api/service"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	general_i "github.com/beka-birhanu/vinom-common/interfaces/general"
+	logger "github.com/beka-birhanu/vinom-common/log"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/grpc"
+)
+
+// Deps are the external dependencies of an App: storage and the game backend services.
+// New builds them from the config; NewWithDeps accepts any implementation, e.g. in-memory fakes.
+type Deps struct {
+	Users       i.UserRepo
+	Replays     i.ReplayRepo
+	Events      i.EventRepo
+	Matches     i.MatchRepo
+	Friendships i.FriendshipRepo
+	Seasons     i.SeasonRepo
+	Sessions    i.GameSessionManager
+	Matchmaker  i.Matchmaker
+
+	Metrics    *metrics.Registry          // Nil creates an empty registry
+	HeavyReads *readpref.ReadPref         // Read preference of heavy reads; nil reports primary consistency
+	Checks     map[string]healthapi.Check // Readiness checks served on /readyz
+
+	// Connections exercised by the selftest command; nil skips the check.
+	MatchmakerConn     *grpc.ClientConn
+	SessionManagerConn *grpc.ClientConn
+
+	Close func(ctx context.Context) error // Releases the dependencies on Stop; may be nil
+}
+
+// App is the wired API: services, controllers and the public and admin listeners.
+type App struct {
+	cfg    config.Config
+	deps   Deps
+	logger general_i.Logger
+
+	tokenizer     i.Tokenizer
+	auth          i.Authenticator
+	seasons       i.Seasons
+	rateLimiter   i.AdjustableRateLimiter
+	apiKeyLimiter i.AdjustableRateLimiter
+	publicLimiter i.AdjustableRateLimiter
+	adminLimiter  i.AdjustableRateLimiter
+
+	router      *api.Router
+	adminRouter *api.Router
+	servers     []*http.Server
+	errs        chan error
+}
+
+// New connects to the configured database and game backend services and wires the App.
+func New(cfg config.Config) (*App, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	deps, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := NewWithDeps(cfg, deps)
+	if err != nil {
+		if deps.Close != nil {
+			_ = deps.Close(ctx)
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewWithDeps wires an App around the given dependencies without connecting to anything.
+func NewWithDeps(cfg config.Config, deps Deps) (*App, error) {
+	appLogger, err := logger.New("APP", config.ColorGreen, os.Stdout)
+	if err != nil {
+		return nil, fmt.Errorf("creating app logger: %w", err)
+	}
+
+	if deps.Metrics == nil {
+		deps.Metrics = metrics.NewRegistry()
+	}
+	deps.Metrics.NewGauge("build_info", "Build information of the running binary; always 1.", "version", "commit", "goVersion").
+		Set(1, config.Version, config.Commit, runtime.Version())
+
+	a := &App{
+		cfg:           cfg,
+		deps:          deps,
+		logger:        appLogger,
+		tokenizer:     token.NewJwtService(cfg.JWTSecret, cfg.JWTIssuer),
+		rateLimiter:   infra_ratelimit.NewTokenBucket(float64(cfg.RateLimitRPS), cfg.RateLimitBurst),
+		apiKeyLimiter: infra_ratelimit.NewTokenBucket(float64(cfg.APIKeyRateLimitRPS), 2*cfg.APIKeyRateLimitRPS),
+		publicLimiter: infra_ratelimit.NewTokenBucket(float64(cfg.PublicRateLimitRPS), 2*cfg.PublicRateLimitRPS),
+		adminLimiter:  infra_ratelimit.NewTokenBucket(float64(cfg.AdminRateLimitRPS), 2*cfg.AdminRateLimitRPS),
+		errs:          make(chan error, 2),
+	}
+
+	filter, err := newContentFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	a.auth, err = service.NewAuthService(deps.Users, deps.Replays, deps.Matches, deps.Friendships, a.tokenizer, filter, cfg.PlacementMatches)
+	if err != nil {
+		return nil, fmt.Errorf("creating auth service: %w", err)
+	}
+	spectator, err := service.NewSpectatorService(deps.Sessions, a.tokenizer)
+	if err != nil {
+		return nil, fmt.Errorf("creating spectator service: %w", err)
+	}
+	leaderboard, err := service.NewLeaderboardService(deps.Users)
+	if err != nil {
+		return nil, fmt.Errorf("creating leaderboard service: %w", err)
+	}
+	a.seasons, err = service.NewSeasonsService(deps.Seasons, deps.Users, dmn.SeasonReset{
+		Mean:             cfg.SeasonResetMean,
+		Keep:             float64(cfg.SeasonResetKeep) / 100,
+		PlacementMatches: cfg.PlacementMatches,
+	}, cfg.SeasonStandings)
+	if err != nil {
+		return nil, fmt.Errorf("creating seasons service: %w", err)
+	}
+	matchHistory, err := service.NewMatchHistoryService(deps.Matches, deps.Users)
+	if err != nil {
+		return nil, fmt.Errorf("creating match history service: %w", err)
+	}
+	friends, err := service.NewFriendsService(deps.Friendships, deps.Users)
+	if err != nil {
+		return nil, fmt.Errorf("creating friends service: %w", err)
+	}
+
+	heavyReads := a.heavyReadConsistency()
+	defaultLatency := time.Duration(cfg.DefaultLatency) * time.Millisecond
+	matchmaking, err := gameapi.NewMatchMakingController(deps.Sessions, deps.Users, deps.Matchmaker, spectator, latency.NewProber(), defaultLatency, ratelimit.PerUser(a.rateLimiter), idempotency.Keys(10*time.Minute))
+	if err != nil {
+		return nil, fmt.Errorf("creating matchmaking controller: %w", err)
+	}
+
+	a.router = api.NewRouter(api.Config{
+		Addr:    fmt.Sprintf("%s:%v", cfg.HostIP, cfg.RESTPort),
+		BaseURL: "/api",
+		Controllers: []api_i.Controller{
+			identity.NewIdentityServer(a.auth, ratelimit.PerIP(a.rateLimiter)),
+			matchmaking,
+			replayapi.NewReplayController(deps.Replays, heavyReads),
+			leaderboardapi.NewLeaderboardController(leaderboard, a.seasons, heavyReads),
+			matchapi.NewMatchHistoryController(matchHistory, heavyReads),
+			friendsapi.NewFriendsController(friends, ratelimit.PerUser(a.rateLimiter)),
+			eventapi.NewEventController(deps.Events),
+			statsapi.NewPublicStatsController(leaderboard, matchHistory, ratelimit.ByAPIKey(cfg.PublicAPIKeys, a.apiKeyLimiter, a.publicLimiter), heavyReads),
+			versionapi.NewVersionController(config.Version, config.Commit, config.BuildTime),
+		},
+		AuthorizationMiddleware: identity.Authoriz(a.tokenizer),
+		Middlewares: []gin.HandlerFunc{
+			response.Middleware(cfg.LegacyResponses),
+			metricsapi.Instrument(deps.Metrics),
+			deadline.Middleware(time.Duration(cfg.RequestTimeout) * time.Millisecond),
+		},
+		TrustedProxies: cfg.TrustedProxies,
+	})
+
+	// The admin listener serves the diagnostic endpoints and is not exposed through
+	// the public ingress. Health probes need no token; everything else requires one
+	// of ADMIN_TOKENS.
+	if len(cfg.AdminTokens) == 0 {
+		appLogger.Info("ADMIN_TOKENS is not set; protected admin endpoints reject every request")
+	}
+	a.adminRouter = api.NewRouter(api.Config{
+		Addr:    fmt.Sprintf("%s:%v", cfg.AdminHost, cfg.AdminPort),
+		BaseURL: "/admin",
+		Controllers: []api_i.Controller{
+			healthapi.NewHealthController(5*time.Second, deps.Checks),
+			metricsapi.NewMetricsController(deps.Metrics),
+			usersapi.NewUsersController(deps.Users, heavyReads),
+		},
+		AuthorizationMiddleware: identity.AdminTokens(cfg.AdminTokens),
+		Middlewares: []gin.HandlerFunc{
+			response.Middleware(false),
+			ratelimit.PerIP(a.adminLimiter),
+		},
+		TrustedProxies: cfg.TrustedProxies,
+	})
+
+	return a, nil
+}
+
+// heavyReadConsistency reports the staleness bound of heavy reads in response meta.
+func (a *App) heavyReadConsistency() gin.HandlerFunc {
+	if a.deps.HeavyReads == nil {
+		return response.ReadConsistency(readpref.PrimaryMode.String(), 0)
+	}
+	maxStaleness, _ := a.deps.HeavyReads.MaxStaleness()
+	return response.ReadConsistency(a.deps.HeavyReads.Mode().String(), maxStaleness)
+}
+
+// newContentFilter builds the filter for user supplied names from the word list
+// and, if configured, the external moderation service.
+func newContentFilter(cfg config.Config) (i.ContentFilter, error) {
+	filter := contentfilter.NewDefaultWordlist()
+	if cfg.ContentFilterList != "" {
+		var err error
+		filter, err = contentfilter.LoadWordlist(cfg.ContentFilterList)
+		if err != nil {
+			return nil, fmt.Errorf("loading content filter word list: %w", err)
+		}
+	}
+
+	if cfg.ContentFilterURL != "" {
+		remote := contentfilter.NewRemote(cfg.ContentFilterURL, time.Duration(cfg.RPCTimeout)*time.Millisecond)
+		return contentfilter.NewChain(filter, remote), nil
+	}
+	return filter, nil
+}
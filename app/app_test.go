@@ -0,0 +1,186 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/config"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/grpc/grpctest"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testConfig() config.Config {
+	return config.Config{
+		HostIP:             "127.0.0.1",
+		AdminHost:          "127.0.0.1",
+		JWTSecret:          "secret",
+		JWTIssuer:          "vinom",
+		RateLimitRPS:       5,
+		RateLimitBurst:     10,
+		PublicRateLimitRPS: 1,
+		APIKeyRateLimitRPS: 20,
+		AdminRateLimitRPS:  10,
+		RequestTimeout:     10000,
+	}
+}
+
+func TestApp(t *testing.T) {
+	t.Run("Serve routes without connecting anything", func(t *testing.T) {
+		a, err := NewWithDeps(testConfig(), Deps{})
+		assert.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		a.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/version", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		a.AdminHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/v1/readyz", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Start and stop listeners", func(t *testing.T) {
+		closed := false
+		a, err := NewWithDeps(testConfig(), Deps{Close: func(context.Context) error {
+			closed = true
+			return nil
+		}})
+		assert.NoError(t, err)
+
+		assert.NoError(t, a.Start())
+		assert.Len(t, a.servers, 2)
+		assert.NoError(t, a.Stop(context.Background()))
+		assert.True(t, closed)
+	})
+}
+
+func TestMatchmakingWithFakes(t *testing.T) {
+	sessions := grpctest.NewInMemorySessionManager()
+	users, replays := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryReplayRepo()
+	a, err := NewWithDeps(testConfig(), Deps{
+		Users:       users,
+		Replays:     replays,
+		Events:      repotest.NewInMemoryEventRepo(),
+		Matches:     repotest.NewInMemoryMatchRepo(),
+		Friendships: repotest.NewInMemoryFriendshipRepo(),
+		Seasons:     repotest.NewInMemorySeasonRepo(),
+		Sessions:    sessions,
+		Matchmaker:  grpctest.NewInMemoryMatchmaker(2, sessions, []byte("key"), "127.0.0.1:9000"),
+	})
+	assert.NoError(t, err)
+	handler := a.Handler()
+
+	// Seeded with a cheap hash; registering would spend seconds in bcrypt.
+	// Only abebe has a placement match left.
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-horse-battery-staple"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	for idx, username := range []string{"abebe", "bekele"} {
+		user := &dmn.User{ID: uuid.New(), Username: username, PasswordHash: string(hash), Rating: 1500, PlacementMatchesLeft: 1 - idx}
+		assert.NoError(t, users.Save(context.Background(), user))
+	}
+
+	serve := func(method, path, token, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		var res struct {
+			Data map[string]any `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		return w.Code, res.Data
+	}
+
+	players := make([]map[string]any, 0, 2)
+	for _, username := range []string{"abebe", "bekele"} {
+		code, player := serve(http.MethodPost, "/api/v1/auth/login", "", `{"username":"`+username+`","password":"correct-horse-battery-staple"}`)
+		assert.Equal(t, http.StatusOK, code)
+		players = append(players, player)
+	}
+
+	// Players can only queue themselves.
+	code, _ := serve(http.MethodPost, "/api/v1/gameMatch/", players[0]["authToken"].(string), `{"id":"`+players[1]["id"].(string)+`"}`)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _ = serve(http.MethodPost, "/api/v1/gameMatch/", players[0]["authToken"].(string), `{"id":"`+players[0]["id"].(string)+`"}`)
+	assert.Equal(t, http.StatusAccepted, code)
+	code, _ = serve(http.MethodPost, "/api/v1/gameMatch/", players[1]["authToken"].(string), "")
+	assert.Equal(t, http.StatusAccepted, code)
+
+	code, info := serve(http.MethodGet, "/api/v1/gameMatch/"+players[0]["id"].(string), players[0]["authToken"].(string), "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "127.0.0.1:9000", info["socketAddr"])
+
+	code, spectate := serve(http.MethodGet, "/api/v1/gameMatch/"+players[0]["id"].(string)+"/spectate", players[1]["authToken"].(string), "")
+	assert.Equal(t, http.StatusOK, code)
+	spectatorToken := spectate["spectatorToken"].(string)
+
+	code, _ = serve(http.MethodGet, "/api/v1/auth/me", players[1]["authToken"].(string), "")
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serve(http.MethodGet, "/api/v1/auth/me", spectatorToken, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = serve(http.MethodPatch, "/api/v1/auth/me", spectatorToken, `{"username":"mallory"}`)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	serviceToken, err := a.auth.ServiceToken(context.Background(), "replay-recorder", nil, time.Minute)
+	assert.NoError(t, err)
+	code, _ = serve(http.MethodGet, "/api/v1/auth/me", serviceToken, "")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	// Retried match reports succeed without recording the match twice.
+	recorder, err := a.auth.ServiceToken(context.Background(), "session-manager", []string{"matches"}, time.Minute)
+	assert.NoError(t, err)
+	report := `{"id":"` + uuid.NewString() + `","mazeWidth":5,"mazeHeight":5,"startedAt":"2026-01-01T10:00:00Z","endedAt":"2026-01-01T10:05:00Z",` +
+		`"players":[{"playerId":"` + players[0]["id"].(string) + `","score":3},{"playerId":"` + players[1]["id"].(string) + `","score":1}]}`
+	placements := func(match map[string]any) []any {
+		flags := make([]any, 0, 2)
+		for _, player := range match["players"].([]any) {
+			flags = append(flags, player.(map[string]any)["placement"])
+		}
+		return flags
+	}
+	code, recorded := serve(http.MethodPost, "/api/v1/matches/", recorder, report)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, []any{true, false}, placements(recorded))
+	code, recorded = serve(http.MethodPost, "/api/v1/matches/", recorder, report)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []any{true, false}, placements(recorded))
+	twice := strings.Replace(report, players[1]["id"].(string), players[0]["id"].(string), 1)
+	code, _ = serve(http.MethodPost, "/api/v1/matches/", recorder, twice)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Replays are uploaded with a service token, and only players of a match can stream them.
+	replay := &dmn.Replay{ID: uuid.New()}
+	upload := `{"id":"` + replay.ID.String() + `","playerIds":["` + players[0]["id"].(string) + `"],"startedAt":"2026-01-01T10:00:00Z","endedAt":"2026-01-01T10:05:00Z",` +
+		`"frames":[{"version":1,"at":1767261600000,"playerId":"` + players[0]["id"].(string) + `","state":"e30="}]}`
+	code, _ = serve(http.MethodPost, "/api/v1/replays/", players[0]["authToken"].(string), upload)
+	assert.Equal(t, http.StatusForbidden, code)
+	uploader, err := a.auth.ServiceToken(context.Background(), "session-manager", []string{"replays"}, time.Minute)
+	assert.NoError(t, err)
+	code, _ = serve(http.MethodPost, "/api/v1/replays/", uploader, upload)
+	assert.Equal(t, http.StatusCreated, code)
+	listed, err := replays.ByPlayer(context.Background(), uuid.MustParse(players[0]["id"].(string)), 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+	code, _ = serve(http.MethodGet, "/api/v1/replays/?pageSize=500", players[0]["authToken"].(string), "")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(http.MethodGet, "/api/v1/replays/"+replay.ID.String()+"/stream", players[1]["authToken"].(string), "")
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = serve(http.MethodGet, "/api/v1/replays/"+uuid.NewString()+"/stream", players[0]["authToken"].(string), "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, frame := serve(http.MethodGet, "/api/v1/replays/"+replay.ID.String()+"/stream", players[0]["authToken"].(string), "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, frame) // Frames are streamed as bare JSON lines, not a data envelope.
+}
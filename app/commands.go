@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// serviceTokenTTL is the lifetime of tokens issued by the service-token command.
+const serviceTokenTTL = 30 * 24 * time.Hour
+
+// RunCommand runs an administration command and reports whether it succeeded:
+//
+//	grant-role <username> <role>       grants a role, e.g. admin, to a user
+//	service-token <service> [scope...] prints a token for another backend service
+//	end-season                         ends the ranked season and soft-resets ratings
+func (a *App) RunCommand(ctx context.Context, name string, args []string) bool {
+	switch name {
+	case "grant-role":
+		if len(args) != 2 {
+			fmt.Println("usage: vinomapi grant-role <username> <role>")
+			return false
+		}
+		if err := a.auth.GrantRole(ctx, args[0], args[1]); err != nil {
+			fmt.Printf("granting role: %v\n", err)
+			return false
+		}
+		fmt.Printf("granted role %s to %s\n", args[1], args[0])
+		return true
+
+	case "service-token":
+		if len(args) < 1 {
+			fmt.Println("usage: vinomapi service-token <service> [scope...]")
+			return false
+		}
+		token, err := a.auth.ServiceToken(ctx, args[0], args[1:], serviceTokenTTL)
+		if err != nil {
+			fmt.Printf("issuing service token: %v\n", err)
+			return false
+		}
+		fmt.Println(token)
+		return true
+
+	case "end-season":
+		season, err := a.seasons.End(ctx)
+		if err != nil {
+			fmt.Printf("ending season: %v\n", err)
+			return false
+		}
+		fmt.Printf("ended season %d with %d players in the final standings\n", season.Number, len(season.Standings))
+		return true
+	}
+
+	fmt.Printf("unknown command %q\n", name)
+	return false
+}
@@ -0,0 +1,218 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	healthapi "github.com/beka-birhanu/vinom-api/api/health"
+	"github.com/beka-birhanu/vinom-api/config"
+	"github.com/beka-birhanu/vinom-api/infrastruture/correlation"
+	"github.com/beka-birhanu/vinom-api/infrastruture/grpc/grpctls"
+	grpc_matchmaking "github.com/beka-birhanu/vinom-api/infrastruture/grpc/matchmaking"
+	"github.com/beka-birhanu/vinom-api/infrastruture/grpc/resilience"
+	grpc_sessionmanager "github.com/beka-birhanu/vinom-api/infrastruture/grpc/sessionmanager"
+	"github.com/beka-birhanu/vinom-api/infrastruture/metrics"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo"
+	logger "github.com/beka-birhanu/vinom-common/log"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// connectTimeout bounds connecting to MongoDB when the App is created.
+const connectTimeout = 60 * time.Second
+
+// connect builds the production dependencies: MongoDB repositories and gRPC clients
+// of the matchmaking and session manager services.
+func connect(ctx context.Context, cfg config.Config) (Deps, error) {
+	registry := metrics.NewRegistry()
+
+	heavyReads, err := heavyReadPref(cfg)
+	if err != nil {
+		return Deps{}, err
+	}
+
+	uri := fmt.Sprintf("mongodb://%s:%s@%s:%v", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort)
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetMonitor(metrics.CommandMonitor(registry)))
+	if err != nil {
+		return Deps{}, fmt.Errorf("connecting to MongoDB: %w", err)
+	}
+	if err = mongoClient.Ping(ctx, nil); err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, fmt.Errorf("MongoDB ping failed: %w", err)
+	}
+	users := repo.NewUserRepo(mongoClient, cfg.DBName, "users", heavyReads)
+	if err = users.EnsureIndexes(ctx); err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, fmt.Errorf("creating user indexes: %w", err)
+	}
+	replays := repo.NewReplayRepo(mongoClient, cfg.DBName, "replays", "replayFrames", heavyReads)
+	if err = replays.EnsureIndexes(ctx); err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, fmt.Errorf("creating replay indexes: %w", err)
+	}
+	matches := repo.NewMatchRepo(mongoClient, cfg.DBName, "matches", heavyReads)
+	if err = matches.EnsureIndexes(ctx); err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, fmt.Errorf("creating match indexes: %w", err)
+	}
+	friendships := repo.NewFriendshipRepo(mongoClient, cfg.DBName, "friendships")
+	if err = friendships.EnsureIndexes(ctx); err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, fmt.Errorf("creating friendship indexes: %w", err)
+	}
+
+	matchmakerConn, sessionManagerConn, err := dialGrpc(cfg, registry)
+	if err != nil {
+		_ = mongoClient.Disconnect(ctx)
+		return Deps{}, err
+	}
+	closeAll := func(ctx context.Context) error {
+		return errors.Join(
+			matchmakerConn.Close(),
+			sessionManagerConn.Close(),
+			mongoClient.Disconnect(ctx),
+		)
+	}
+
+	rpcTimeout := time.Duration(cfg.RPCTimeout) * time.Millisecond
+	sessionLogger, err := logger.New("SESSION-MANAGER", config.ColorCyan, os.Stdout)
+	if err != nil {
+		_ = closeAll(ctx)
+		return Deps{}, fmt.Errorf("creating session manager logger: %w", err)
+	}
+	sessions, err := grpc_sessionmanager.NewClient(sessionManagerConn, sessionLogger, rpcTimeout)
+	if err != nil {
+		_ = closeAll(ctx)
+		return Deps{}, fmt.Errorf("creating grpc session client: %w", err)
+	}
+
+	matchLogger, err := logger.New("MATCH-MAKER", config.ColorPurple, os.Stdout)
+	if err != nil {
+		_ = closeAll(ctx)
+		return Deps{}, fmt.Errorf("creating matchmaker logger: %w", err)
+	}
+	matchmaker, err := grpc_matchmaking.NewClient(matchmakerConn, matchLogger, rpcTimeout)
+	if err != nil {
+		_ = closeAll(ctx)
+		return Deps{}, fmt.Errorf("creating grpc matchmaker client: %w", err)
+	}
+
+	return Deps{
+		Users:       users,
+		Replays:     replays,
+		Events:      repo.NewEventRepo(mongoClient, cfg.DBName, "events"),
+		Matches:     matches,
+		Friendships: friendships,
+		Seasons:     repo.NewSeasonRepo(mongoClient, cfg.DBName, "seasons"),
+		Sessions:    sessions,
+		Matchmaker:  matchmaker,
+		Metrics:     registry,
+		HeavyReads:  heavyReads,
+		Checks: map[string]healthapi.Check{
+			"mongo": func(ctx context.Context) error {
+				return mongoClient.Ping(ctx, nil)
+			},
+			"matchmaking": func(ctx context.Context) error {
+				return checkGrpcConn(ctx, matchmakerConn)
+			},
+			"sessionManager": func(ctx context.Context) error {
+				return checkGrpcConn(ctx, sessionManagerConn)
+			},
+		},
+		MatchmakerConn:     matchmakerConn,
+		SessionManagerConn: sessionManagerConn,
+		Close:              closeAll,
+	}, nil
+}
+
+// heavyReadPref builds the read preference of heavy read endpoints from DB_READ_PREFERENCE
+// and DB_MAX_STALENESS.
+func heavyReadPref(cfg config.Config) (*readpref.ReadPref, error) {
+	mode, err := readpref.ModeFromString(cfg.DBReadPreference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_READ_PREFERENCE: %w", err)
+	}
+
+	var opts []readpref.Option
+	if cfg.DBMaxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(time.Duration(cfg.DBMaxStaleness)*time.Second))
+	}
+	pref, err := readpref.New(mode, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid heavy read preference: %w", err)
+	}
+	return pref, nil
+}
+
+// dialGrpc creates the matchmaking and session manager connections.
+// Each connection gets its own breaker; metrics record every individual attempt.
+func dialGrpc(cfg config.Config, registry *metrics.Registry) (*grpc.ClientConn, *grpc.ClientConn, error) {
+	resilienceConfig := resilience.Config{
+		MaxAttempts:      cfg.RPCMaxAttempts,
+		BaseBackoff:      time.Duration(cfg.RPCBackoff) * time.Millisecond,
+		MaxBackoff:       time.Duration(cfg.RPCTimeout) * time.Millisecond,
+		AttemptTimeout:   time.Duration(cfg.RPCAttemptTimeout) * time.Millisecond,
+		FailureThreshold: cfg.RPCBreakerFailures,
+		Cooldown:         time.Duration(cfg.RPCBreakerCooldown) * time.Millisecond,
+	}
+	grpcMetrics := metrics.UnaryClientInterceptor(registry)
+	newInterceptors := func() grpc.DialOption {
+		return grpc.WithChainUnaryInterceptor(
+			correlation.UnaryClientInterceptor(),
+			resilience.UnaryClientInterceptor(resilienceConfig),
+			grpcMetrics,
+		)
+	}
+
+	// Connections are not encrypted unless GRPC_TLS_CA is set.
+	var transportCredentials credentials.TransportCredentials = insecure.NewCredentials()
+	if cfg.GRPCTLSCA != "" {
+		var err error
+		transportCredentials, err = grpctls.NewClientCredentials(grpctls.Config{
+			CAFile:     cfg.GRPCTLSCA,
+			CertFile:   cfg.GRPCTLSCert,
+			KeyFile:    cfg.GRPCTLSKey,
+			ServerName: cfg.GRPCTLSServerName,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading gRPC TLS credentials: %w", err)
+		}
+	}
+
+	matchmakingAddr := fmt.Sprintf("%s:%d", cfg.MatchmakingHost, cfg.MatchmakingPort)
+	matchmakerConn, err := grpc.NewClient(matchmakingAddr, grpc.WithTransportCredentials(transportCredentials), newInterceptors())
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating matchmaking gRPC connection: %w", err)
+	}
+
+	sessionManagerAddr := fmt.Sprintf("%s:%d", cfg.SessionManagerHost, cfg.SessionManagerPort)
+	sessionManagerConn, err := grpc.NewClient(sessionManagerAddr, grpc.WithTransportCredentials(transportCredentials), newInterceptors())
+	if err != nil {
+		_ = matchmakerConn.Close()
+		return nil, nil, fmt.Errorf("creating session manager gRPC connection: %w", err)
+	}
+
+	return matchmakerConn, sessionManagerConn, nil
+}
+
+// checkGrpcConn waits until the connection is ready or ctx is done.
+func checkGrpcConn(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection stuck in %s", state)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/beka-birhanu/vinom-api/config"
+	"github.com/beka-birhanu/vinom-api/infrastruture/grpc/grpctest"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+)
+
+// devPubKey stands in for the game socket's public key in dev mode.
+var devPubKey = []byte("vinom-dev-socket-key")
+
+// NewDev wires an App for local development: in-memory storage, a local
+// matchmaker that fills matches in arrival order, and a demo user. Nothing is
+// persisted across restarts. Matched players are sent to cfg.DevSocketAddr,
+// where a locally run game server can pick them up.
+func NewDev(cfg config.Config) (*App, error) {
+	sessions := grpctest.NewInMemorySessionManager()
+	a, err := NewWithDeps(cfg, Deps{
+		Users:       repotest.NewInMemoryUserRepo(),
+		Replays:     repotest.NewInMemoryReplayRepo(),
+		Events:      repotest.NewInMemoryEventRepo(),
+		Matches:     repotest.NewInMemoryMatchRepo(),
+		Friendships: repotest.NewInMemoryFriendshipRepo(),
+		Seasons:     repotest.NewInMemorySeasonRepo(),
+		Sessions:    sessions,
+		Matchmaker:  grpctest.NewInMemoryMatchmaker(cfg.DevMatchSize, sessions, devPubKey, cfg.DevSocketAddr),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.DevDemoUsername != "" {
+		if err := a.auth.Register(context.Background(), cfg.DevDemoUsername, cfg.DevDemoPassword); err != nil {
+			return nil, fmt.Errorf("registering demo user: %w", err)
+		}
+		a.logger.Info(fmt.Sprintf("Dev mode: log in as %s with password %s", cfg.DevDemoUsername, cfg.DevDemoPassword))
+	}
+	a.logger.Info(fmt.Sprintf("Dev mode: admin token %v; matched players are sent to %s", cfg.AdminTokens, cfg.DevSocketAddr))
+	return a, nil
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/beka-birhanu/vinom-api/api"
+	"github.com/beka-birhanu/vinom-api/config"
+)
+
+// Start binds the public and admin listeners and serves them in the background.
+// Listener errors after Start returns are delivered on Err.
+func (a *App) Start() error {
+	for _, r := range []*api.Router{a.router, a.adminRouter} {
+		listener, err := net.Listen("tcp", r.Addr())
+		if err != nil {
+			_ = a.Stop(context.Background())
+			return fmt.Errorf("listening on %s: %w", r.Addr(), err)
+		}
+
+		server := &http.Server{Handler: r.Handler()}
+		a.servers = append(a.servers, server)
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.errs <- fmt.Errorf("serving %s: %w", r.Addr(), err)
+			}
+		}()
+		a.logger.Info(fmt.Sprintf("Listening on %s", r.Addr()))
+	}
+	return nil
+}
+
+// Err receives the error of a listener that stopped serving on its own.
+func (a *App) Err() <-chan error {
+	return a.errs
+}
+
+// Stop gracefully shuts the listeners down, waiting for in-flight requests until
+// ctx is done, and then releases the dependencies.
+func (a *App) Stop(ctx context.Context) error {
+	var errs []error
+	for _, server := range a.servers {
+		errs = append(errs, server.Shutdown(ctx))
+	}
+	a.servers = nil
+
+	if a.deps.Close != nil {
+		errs = append(errs, a.deps.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Reload applies the reloadable values of cfg, the rate limits, to the running App.
+func (a *App) Reload(cfg config.Config) {
+	a.rateLimiter.SetLimits(float64(cfg.RateLimitRPS), cfg.RateLimitBurst)
+	a.apiKeyLimiter.SetLimits(float64(cfg.APIKeyRateLimitRPS), 2*cfg.APIKeyRateLimitRPS)
+	a.publicLimiter.SetLimits(float64(cfg.PublicRateLimitRPS), 2*cfg.PublicRateLimitRPS)
+	a.adminLimiter.SetLimits(float64(cfg.AdminRateLimitRPS), 2*cfg.AdminRateLimitRPS)
+	a.logger.Info("Config reloaded")
+}
+
+// Handler returns the HTTP handler of the public API, for serving it without Start.
+func (a *App) Handler() http.Handler {
+	return a.router.Handler()
+}
+
+// AdminHandler returns the HTTP handler of the admin listener, for serving it without Start.
+func (a *App) AdminHandler() http.Handler {
+	return a.adminRouter.Handler()
+}
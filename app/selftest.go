@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+// selfTestCheck is a single named check run by the selftest command.
+type selfTestCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// SelfTest exercises critical paths against the configured dependencies,
+// prints a pass/fail report, and reports whether every check passed.
+func (a *App) SelfTest(ctx context.Context) bool {
+	checks := []selfTestCheck{
+		{name: "mongo: write, read, and delete a test user", run: a.checkUserRoundTrip},
+		{name: "jwt: generate and decode a token", run: a.checkTokenRoundTrip},
+	}
+	conns := []struct {
+		name string
+		conn *grpc.ClientConn
+	}{
+		{name: "grpc: reach matchmaking service", conn: a.deps.MatchmakerConn},
+		{name: "grpc: reach session manager service", conn: a.deps.SessionManagerConn},
+	}
+	for _, c := range conns {
+		if c.conn == nil {
+			continue
+		}
+		conn := c.conn
+		checks = append(checks, selfTestCheck{name: c.name, run: func(ctx context.Context) error {
+			return checkGrpcConn(ctx, conn)
+		}})
+	}
+
+	passed := true
+	fmt.Println("vinom-api selftest")
+	for _, c := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := c.run(checkCtx)
+		cancel()
+
+		if err != nil {
+			passed = false
+			fmt.Printf("  [FAIL] %s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Printf("  [PASS] %s\n", c.name)
+	}
+
+	if passed {
+		fmt.Println("all checks passed")
+	} else {
+		fmt.Println("some checks failed")
+	}
+	return passed
+}
+
+func (a *App) checkUserRoundTrip(ctx context.Context) error {
+	id := uuid.New()
+	user := &dmn.User{
+		ID:       id,
+		Username: "selftest_" + id.String()[:8],
+	}
+
+	if err := a.deps.Users.Save(ctx, user); err != nil {
+		return fmt.Errorf("save: %w", err)
+	}
+	defer func() {
+		_ = a.deps.Users.Delete(context.WithoutCancel(ctx), id)
+	}()
+
+	stored, err := a.deps.Users.ByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	if stored.Username != user.Username {
+		return errors.New("read back a different user")
+	}
+
+	return a.deps.Users.Delete(ctx, id)
+}
+
+func (a *App) checkTokenRoundTrip(_ context.Context) error {
+	token, err := a.tokenizer.Generate(map[string]interface{}{"selftest": true}, time.Minute)
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	claims, err := a.tokenizer.Decode(token)
+	if err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	if claims["selftest"] != true {
+		return errors.New("claims did not survive the round trip")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package config
+
+// Build information, set at link time, e.g.:
+//
+//	go build -ldflags "-X github.com/beka-birhanu/vinom-api/config.Version=v1.2.0"
+//
+// See the build target of the Makefile.
+var (
+	Version   = "dev"     // Release version
+	Commit    = "unknown" // Git commit the binary was built from
+	BuildTime = "unknown" // UTC build time in RFC 3339 format
+)
@@ -1,86 +1,341 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds the application's configuration values.
+// Values are layered: defaults, then the YAML file named by CONFIG_FILE, then environment variables.
 type Config struct {
-	HostIP             string // Host IP for the server
-	RESTPort           int    // Port for the REST API
-	DBHost             string // Hostname or IP address for the database
-	DBPort             int    // Port number for the database
-	DBUser             string // Username for the database
-	DBPassword         string // Password for the database
-	DBName             string // Name of the database
-	GinMode            string // Mode for the Gin framework (e.g., release, debug, test)
-	JWTSecret          string // Secret key for JWT signing
-	JWTIssuer          string // Issuer claim for JWTs
-	MatchmakingHost    string // Hostname or IP address for the Matchmaiking server
-	MatchmakingPort    int    // Port number for the Matchmaiking server
-	SessionManagerHost string // Hostname or IP address for the session manager server
-	SessionManagerPort int    // Port number for the session manager server
-	RPCTimeout         int    // Timeout duration for rpc calles
+	HostIP             string   `yaml:"hostIP"`             // Host IP for the server
+	RESTPort           int      `yaml:"restPort"`           // Port for the REST API
+	DBHost             string   `yaml:"dbHost"`             // Hostname or IP address for the database
+	DBPort             int      `yaml:"dbPort"`             // Port number for the database
+	DBUser             string   `yaml:"dbUser"`             // Username for the database
+	DBPassword         string   `yaml:"dbPassword"`         // Password for the database
+	DBName             string   `yaml:"dbName"`             // Name of the database
+	DBReadPreference   string   `yaml:"dbReadPreference"`   // Read preference of heavy read endpoints, e.g. secondaryPreferred
+	DBMaxStaleness     int      `yaml:"dbMaxStaleness"`     // Seconds a secondary may lag to serve heavy reads; 0 leaves it unbounded
+	GinMode            string   `yaml:"ginMode"`            // Mode for the Gin framework (e.g., release, debug, test)
+	JWTSecret          string   `yaml:"jwtSecret"`          // Secret key for JWT signing
+	JWTIssuer          string   `yaml:"jwtIssuer"`          // Issuer claim for JWTs
+	MatchmakingHost    string   `yaml:"matchmakingHost"`    // Hostname or IP address for the Matchmaiking server
+	MatchmakingPort    int      `yaml:"matchmakingPort"`    // Port number for the Matchmaiking server
+	SessionManagerHost string   `yaml:"sessionHost"`        // Hostname or IP address for the session manager server
+	SessionManagerPort int      `yaml:"sessionPort"`        // Port number for the session manager server
+	RPCTimeout         int      `yaml:"rpcTimeout"`         // Timeout duration for rpc calles
+	RPCMaxAttempts     int      `yaml:"rpcMaxAttempts"`     // Attempts per rpc call when the service is unavailable
+	RPCBackoff         int      `yaml:"rpcBackoff"`         // Backoff in milliseconds before the first rpc retry
+	RPCAttemptTimeout  int      `yaml:"rpcAttemptTimeout"`  // Timeout in milliseconds for each rpc attempt; 0 uses RPCTimeout only
+	RPCBreakerFailures int      `yaml:"rpcBreakerFailures"` // Consecutive failed rpc calls that open the circuit breaker
+	RPCBreakerCooldown int      `yaml:"rpcBreakerCooldown"` // Milliseconds the circuit breaker stays open
+	GRPCTLSCA          string   `yaml:"grpcTLSCA"`          // CA bundle verifying gRPC servers; empty uses plaintext connections
+	GRPCTLSCert        string   `yaml:"grpcTLSCert"`        // Client certificate presented to gRPC servers for mTLS
+	GRPCTLSKey         string   `yaml:"grpcTLSKey"`         // Private key of the gRPC client certificate
+	GRPCTLSServerName  string   `yaml:"grpcTLSServerName"`  // Overrides the server name verified in gRPC server certificates
+	DefaultLatency     int      `yaml:"defaultLatency"`     // Round trip in milliseconds assumed for players queued without a probe
+	RequestTimeout     int      `yaml:"requestTimeout"`     // Milliseconds a public API request may run before its context is canceled; replay streams are exempt
+	RateLimitRPS       int      `yaml:"rateLimitRPS"`       // Requests per second allowed per client on rate limited routes; reloadable
+	RateLimitBurst     int      `yaml:"rateLimitBurst"`     // Burst size allowed per client on rate limited routes; reloadable
+	PublicAPIKeys      []string `yaml:"publicAPIKeys"`      // API keys granted higher limits on the public stats API
+	PublicRateLimitRPS int      `yaml:"publicRateLimitRPS"` // Requests per second allowed per IP on the public stats API; reloadable
+	APIKeyRateLimitRPS int      `yaml:"apiKeyRateLimitRPS"` // Requests per second allowed per API key on the public stats API; reloadable
+	ContentFilterList  string   `yaml:"contentFilterList"`  // Path to a blocked word list, allowed words prefixed with !; empty uses the built-in list
+	ContentFilterURL   string   `yaml:"contentFilterURL"`   // URL of an external moderation service; empty disables it
+	LegacyResponses    bool     `yaml:"legacyResponses"`    // Serve the pre-envelope snake_case response shapes
+	SeasonResetMean    int      `yaml:"seasonResetMean"`    // Rating every rating moves toward when a season ends
+	SeasonResetKeep    int      `yaml:"seasonResetKeep"`    // Percentage of the distance to SeasonResetMean kept when a season ends
+	PlacementMatches   int      `yaml:"placementMatches"`   // Placement matches granted to every player when a season starts
+	SeasonStandings    int      `yaml:"seasonStandings"`    // Number of top players kept as the final standings of a season
+	AdminHost          string   `yaml:"adminHost"`          // Interface of the admin listener; keep it off the public ingress
+	AdminPort          int      `yaml:"adminPort"`          // Port of the admin listener
+	AdminTokens        []string `yaml:"adminTokens"`        // Bearer tokens accepted on the admin listener
+	AdminRateLimitRPS  int      `yaml:"adminRateLimitRPS"`  // Requests per second allowed per IP on the admin listener; reloadable
+	TrustedProxies     []string `yaml:"trustedProxies"`     // IPs or CIDRs of proxies whose X-Forwarded-For is trusted; empty trusts none
+
+	// Dev mode runs without MongoDB or the game backend; see LoadDev.
+	Dev             bool   `yaml:"-"`
+	DevSocketAddr   string `yaml:"devSocketAddr"`   // Game socket address handed out to matched players in dev mode
+	DevMatchSize    int    `yaml:"devMatchSize"`    // Players per match in dev mode
+	DevDemoUsername string `yaml:"devDemoUsername"` // Username of the demo user registered in dev mode
+	DevDemoPassword string `yaml:"devDemoPassword"` // Password of the demo user registered in dev mode
 }
 
-// Envs holds the application's configuration loaded from environment variables.
-var Envs = initConfig()
+// ConfigFileEnv names the environment variable holding the path of the optional config file.
+const ConfigFileEnv = "CONFIG_FILE"
 
-// initConfig initializes and returns the application configuration.
-// It loads environment variables from a .env file.
-func initConfig() Config {
-	// Load .env file if available
-	if err := godotenv.Load(); err != nil {
-		log.Printf("[APP] [INFO] .env file not found or could not be loaded: %v", err)
-	}
+var loadDotEnv sync.Once
 
-	// Populate the Config struct with required environment variables
+// defaults returns the configuration used for values set neither in the file nor in the environment.
+func defaults() Config {
 	return Config{
-		DBHost:             mustGetEnv("DB_HOST"),
-		DBPort:             mustGetEnvAsInt("DB_PORT"),
-		DBUser:             mustGetEnv("DB_USER"),
-		DBPassword:         mustGetEnv("DB_PASS"),
-		DBName:             mustGetEnv("DB_NAME"),
-		MatchmakingHost:    mustGetEnv("MATCHMAKING_HOST"),
-		MatchmakingPort:    mustGetEnvAsInt("MATCHMAKING_PORT"),
-		SessionManagerHost: mustGetEnv("SESSION_HOST"),
-		SessionManagerPort: mustGetEnvAsInt("SESSION_PORT"),
-		RPCTimeout:         mustGetEnvAsInt("RPC_TIMEOUT"),
-		GinMode:            getEnvWithDefault("GIN_MODE", "release"),
-		JWTSecret:          mustGetEnv("JWT_SECRET"),
-		JWTIssuer:          mustGetEnv("JWT_ISSUER"),
-		HostIP:             mustGetEnv("HOST_IP"),
-		RESTPort:           mustGetEnvAsInt("REST_PORT"),
+		DBReadPreference:   "primary",
+		RPCMaxAttempts:     3,
+		RPCBackoff:         100,
+		RPCBreakerFailures: 5,
+		RPCBreakerCooldown: 10000,
+		GinMode:            "release",
+		DefaultLatency:     250,
+		RequestTimeout:     10000,
+		RateLimitRPS:       5,
+		RateLimitBurst:     10,
+		PublicRateLimitRPS: 1,
+		APIKeyRateLimitRPS: 20,
+		SeasonResetMean:    1400,
+		SeasonResetKeep:    50,
+		PlacementMatches:   10,
+		SeasonStandings:    100,
+		AdminHost:          "127.0.0.1",
+		AdminPort:          9090,
+		AdminRateLimitRPS:  10,
 	}
 }
 
-// mustGetEnv retrieves the value of an environment variable or logs a fatal error if not set.
-func mustGetEnv(key string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		log.Fatalf("[APP] [FATAL] Environment variable %s is not set", key)
+// devDefaults returns the defaults of dev mode: a local server needing no environment at all.
+func devDefaults() Config {
+	cfg := defaults()
+	cfg.Dev = true
+	cfg.HostIP = "127.0.0.1"
+	cfg.RESTPort = 8080
+	cfg.GinMode = "debug"
+	cfg.JWTSecret = "vinom-dev-secret"
+	cfg.JWTIssuer = "vinom-dev"
+	cfg.RPCTimeout = 1000
+	cfg.AdminTokens = []string{"vinom-dev-admin"}
+	cfg.DevSocketAddr = "127.0.0.1:9000"
+	cfg.DevMatchSize = 1
+	cfg.DevDemoUsername = "demo"
+	cfg.DevDemoPassword = "demo-maze-runner-2024"
+	return cfg
+}
+
+// Load builds the configuration from defaults, the YAML file at path and the environment,
+// in increasing order of precedence. An empty path skips the file.
+// Environment variables are also read from a .env file if one exists.
+// All problems found are reported together in the returned error.
+func Load(path string) (Config, error) {
+	return load(path, defaults())
+}
+
+// LoadDev is Load for dev mode. It starts from local defaults and does not
+// require the database and game backend settings.
+func LoadDev(path string) (Config, error) {
+	return load(path, devDefaults())
+}
+
+func load(path string, cfg Config) (Config, error) {
+	loadDotEnv.Do(func() {
+		if err := godotenv.Load(); err != nil {
+			log.Printf("[APP] [INFO] .env file not found or could not be loaded: %v", err)
+		}
+	})
+
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("reading config file: %w", err)
+		}
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true) // Misspelled keys fail loudly instead of being ignored
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
 	}
-	return value
+	return cfg, nil
+}
+
+// applyEnv overrides the configuration with the environment variables that are set.
+func (c *Config) applyEnv() error {
+	env := &envReader{}
+	env.str(&c.DBHost, "DB_HOST")
+	env.int(&c.DBPort, "DB_PORT")
+	env.str(&c.DBUser, "DB_USER")
+	env.str(&c.DBPassword, "DB_PASS")
+	env.str(&c.DBName, "DB_NAME")
+	env.str(&c.DBReadPreference, "DB_READ_PREFERENCE")
+	env.int(&c.DBMaxStaleness, "DB_MAX_STALENESS")
+	env.str(&c.MatchmakingHost, "MATCHMAKING_HOST")
+	env.int(&c.MatchmakingPort, "MATCHMAKING_PORT")
+	env.str(&c.SessionManagerHost, "SESSION_HOST")
+	env.int(&c.SessionManagerPort, "SESSION_PORT")
+	env.int(&c.RPCTimeout, "RPC_TIMEOUT")
+	env.int(&c.RPCMaxAttempts, "RPC_MAX_ATTEMPTS")
+	env.int(&c.RPCBackoff, "RPC_BACKOFF")
+	env.int(&c.RPCAttemptTimeout, "RPC_ATTEMPT_TIMEOUT")
+	env.int(&c.RPCBreakerFailures, "RPC_BREAKER_FAILURES")
+	env.int(&c.RPCBreakerCooldown, "RPC_BREAKER_COOLDOWN")
+	env.str(&c.GRPCTLSCA, "GRPC_TLS_CA")
+	env.str(&c.GRPCTLSCert, "GRPC_TLS_CERT")
+	env.str(&c.GRPCTLSKey, "GRPC_TLS_KEY")
+	env.str(&c.GRPCTLSServerName, "GRPC_TLS_SERVER_NAME")
+	env.str(&c.GinMode, "GIN_MODE")
+	env.str(&c.JWTSecret, "JWT_SECRET")
+	env.str(&c.JWTIssuer, "JWT_ISSUER")
+	env.str(&c.HostIP, "HOST_IP")
+	env.int(&c.RESTPort, "REST_PORT")
+	env.int(&c.DefaultLatency, "DEFAULT_LATENCY")
+	env.int(&c.RequestTimeout, "REQUEST_TIMEOUT")
+	env.int(&c.RateLimitRPS, "RATE_LIMIT_RPS")
+	env.int(&c.RateLimitBurst, "RATE_LIMIT_BURST")
+	env.list(&c.PublicAPIKeys, "PUBLIC_API_KEYS")
+	env.int(&c.PublicRateLimitRPS, "PUBLIC_RATE_LIMIT_RPS")
+	env.int(&c.APIKeyRateLimitRPS, "API_KEY_RATE_LIMIT_RPS")
+	env.str(&c.ContentFilterList, "CONTENT_FILTER_LIST")
+	env.str(&c.ContentFilterURL, "CONTENT_FILTER_URL")
+	env.bool(&c.LegacyResponses, "LEGACY_RESPONSES")
+	env.int(&c.SeasonResetMean, "SEASON_RESET_MEAN")
+	env.int(&c.SeasonResetKeep, "SEASON_RESET_KEEP")
+	env.int(&c.PlacementMatches, "PLACEMENT_MATCHES")
+	env.int(&c.SeasonStandings, "SEASON_STANDINGS")
+	env.str(&c.AdminHost, "ADMIN_HOST")
+	env.int(&c.AdminPort, "ADMIN_PORT")
+	env.list(&c.AdminTokens, "ADMIN_TOKENS")
+	env.int(&c.AdminRateLimitRPS, "ADMIN_RATE_LIMIT_RPS")
+	env.list(&c.TrustedProxies, "TRUSTED_PROXIES")
+	env.str(&c.DevSocketAddr, "DEV_SOCKET_ADDR")
+	env.int(&c.DevMatchSize, "DEV_MATCH_SIZE")
+	env.str(&c.DevDemoUsername, "DEV_DEMO_USERNAME")
+	env.str(&c.DevDemoPassword, "DEV_DEMO_PASSWORD")
+	return errors.Join(env.errs...)
 }
 
-// mustGetEnvAsInt retrieves the value of an environment variable as an integer or logs a fatal error if not set or cannot be parsed.
-func mustGetEnvAsInt(key string) int {
-	valueStr := mustGetEnv(key)
+// Validate reports every missing or out of range value, naming the environment
+// variable and file key that set it.
+func (c Config) Validate() error {
+	var errs []error
+	required := func(set bool, env, key string) {
+		if !set {
+			errs = append(errs, fmt.Errorf("%s (file key %s) is required", env, key))
+		}
+	}
+	port := func(p int, env, key string) {
+		if p < 1 || p > 65535 {
+			errs = append(errs, fmt.Errorf("%s (file key %s) must be a port between 1 and 65535, got %d", env, key, p))
+		}
+	}
+	positive := func(v int, env, key string) {
+		if v < 1 {
+			errs = append(errs, fmt.Errorf("%s (file key %s) must be positive, got %d", env, key, v))
+		}
+	}
+
+	if c.Dev {
+		required(c.DevSocketAddr != "", "DEV_SOCKET_ADDR", "devSocketAddr")
+		positive(c.DevMatchSize, "DEV_MATCH_SIZE", "devMatchSize")
+	} else {
+		required(c.DBHost != "", "DB_HOST", "dbHost")
+		port(c.DBPort, "DB_PORT", "dbPort")
+		required(c.DBUser != "", "DB_USER", "dbUser")
+		required(c.DBPassword != "", "DB_PASS", "dbPassword")
+		required(c.DBName != "", "DB_NAME", "dbName")
+		required(c.MatchmakingHost != "", "MATCHMAKING_HOST", "matchmakingHost")
+		port(c.MatchmakingPort, "MATCHMAKING_PORT", "matchmakingPort")
+		required(c.SessionManagerHost != "", "SESSION_HOST", "sessionHost")
+		port(c.SessionManagerPort, "SESSION_PORT", "sessionPort")
+		positive(c.RPCMaxAttempts, "RPC_MAX_ATTEMPTS", "rpcMaxAttempts")
+	}
+	positive(c.RPCTimeout, "RPC_TIMEOUT", "rpcTimeout")
+	required(c.JWTSecret != "", "JWT_SECRET", "jwtSecret")
+	required(c.JWTIssuer != "", "JWT_ISSUER", "jwtIssuer")
+	required(c.HostIP != "", "HOST_IP", "hostIP")
+	port(c.RESTPort, "REST_PORT", "restPort")
+	port(c.AdminPort, "ADMIN_PORT", "adminPort")
+	positive(c.RequestTimeout, "REQUEST_TIMEOUT", "requestTimeout")
+	positive(c.RateLimitRPS, "RATE_LIMIT_RPS", "rateLimitRPS")
+	positive(c.RateLimitBurst, "RATE_LIMIT_BURST", "rateLimitBurst")
+	positive(c.PublicRateLimitRPS, "PUBLIC_RATE_LIMIT_RPS", "publicRateLimitRPS")
+	positive(c.APIKeyRateLimitRPS, "API_KEY_RATE_LIMIT_RPS", "apiKeyRateLimitRPS")
+	positive(c.AdminRateLimitRPS, "ADMIN_RATE_LIMIT_RPS", "adminRateLimitRPS")
+
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				errs = append(errs, fmt.Errorf("TRUSTED_PROXIES (file key trustedProxies) must list IPs or CIDRs, got %q", proxy))
+			}
+		}
+	}
+	if c.SeasonResetKeep < 0 || c.SeasonResetKeep > 100 {
+		errs = append(errs, fmt.Errorf("SEASON_RESET_KEEP (file key seasonResetKeep) must be a percentage between 0 and 100, got %d", c.SeasonResetKeep))
+	}
+	if c.GRPCTLSCA == "" && (c.GRPCTLSCert != "" || c.GRPCTLSKey != "") {
+		errs = append(errs, errors.New("GRPC_TLS_CERT and GRPC_TLS_KEY need GRPC_TLS_CA (file key grpcTLSCA)"))
+	}
+	if (c.GRPCTLSCert == "") != (c.GRPCTLSKey == "") {
+		errs = append(errs, errors.New("GRPC_TLS_CERT and GRPC_TLS_KEY must be set together"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// envReader overrides config values with set environment variables and collects parse errors.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) str(dst *string, key string) {
+	if value, exists := os.LookupEnv(key); exists {
+		*dst = value
+	}
+}
+
+func (r *envReader) int(dst *int, key string) {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
 	value, err := strconv.Atoi(valueStr)
 	if err != nil {
-		log.Fatalf("[APP] [FATAL] Environment variable %s must be an integer: %v", key, err)
+		r.errs = append(r.errs, fmt.Errorf("environment variable %s must be an integer, got %q", key, valueStr))
+		return
 	}
-	return value
+	*dst = value
 }
 
-// getEnvWithDefault retrieves the value of an environment variable or returns a default value if not set.
-func getEnvWithDefault(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// list reads a comma separated environment variable, dropping empty entries.
+func (r *envReader) list(dst *[]string, key string) {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
+
+	values := make([]string, 0)
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	*dst = values
+}
+
+func (r *envReader) bool(dst *bool, key string) {
+	valueStr, exists := os.LookupEnv(key)
+	if !exists {
+		return
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("environment variable %s must be a boolean, got %q", key, valueStr))
+		return
 	}
-	return defaultValue
+	*dst = value
 }
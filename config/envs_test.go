@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// requiredEnv sets every required value so tests only spell out what they change.
+func requiredEnv(t *testing.T) {
+	for key, value := range map[string]string{
+		"DB_HOST": "db", "DB_PORT": "27017", "DB_USER": "u", "DB_PASS": "p", "DB_NAME": "vinom",
+		"MATCHMAKING_HOST": "mm", "MATCHMAKING_PORT": "50051", "SESSION_HOST": "sm", "SESSION_PORT": "50052",
+		"RPC_TIMEOUT": "1000", "JWT_SECRET": "s", "JWT_ISSUER": "vinom", "HOST_IP": "0.0.0.0", "REST_PORT": "8080",
+	} {
+		t.Setenv(key, value)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Environment overrides file overrides defaults", func(t *testing.T) {
+		requiredEnv(t)
+		t.Setenv("RATE_LIMIT_RPS", "7")
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		writeFile(t, path, "rateLimitRPS: 3\nrateLimitBurst: 4\n")
+
+		cfg, err := Load(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, cfg.RateLimitRPS)
+		assert.Equal(t, 4, cfg.RateLimitBurst)
+		assert.Equal(t, 20, cfg.APIKeyRateLimitRPS)
+		assert.Equal(t, "db", cfg.DBHost)
+	})
+
+	t.Run("Report every problem at once", func(t *testing.T) {
+		requiredEnv(t)
+		t.Setenv("DB_HOST", "")
+		t.Setenv("REST_PORT", "70000")
+		t.Setenv("RATE_LIMIT_BURST", "ten")
+
+		_, err := Load("")
+		assert.ErrorContains(t, err, "RATE_LIMIT_BURST must be an integer")
+
+		t.Setenv("RATE_LIMIT_BURST", "10")
+		_, err = Load("")
+		assert.ErrorContains(t, err, "DB_HOST (file key dbHost) is required")
+		assert.ErrorContains(t, err, "REST_PORT (file key restPort) must be a port")
+
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, proxy.local")
+		_, err = Load("")
+		assert.ErrorContains(t, err, `TRUSTED_PROXIES (file key trustedProxies) must list IPs or CIDRs, got "proxy.local"`)
+	})
+
+	t.Run("Dev mode needs no environment", func(t *testing.T) {
+		cfg, err := LoadDev("")
+		assert.NoError(t, err)
+		assert.True(t, cfg.Dev)
+		assert.Equal(t, "127.0.0.1", cfg.HostIP)
+
+		_, err = Load("")
+		assert.ErrorContains(t, err, "DB_HOST (file key dbHost) is required")
+	})
+
+	t.Run("Reject unknown file keys", func(t *testing.T) {
+		requiredEnv(t)
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		writeFile(t, path, "rateLimitRps: 3\n")
+
+		_, err := Load(path)
+		assert.ErrorContains(t, err, "rateLimitRps")
+	})
+}
+
+func TestWatcher(t *testing.T) {
+	requiredEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "rateLimitRPS: 3\n")
+	current, err := Load(path)
+	assert.NoError(t, err)
+
+	var reloaded []Config
+	var errs []error
+	w := NewWatcher(path, current, func(c Config) { reloaded = append(reloaded, c) }, func(err error) { errs = append(errs, err) })
+	touch := func(content string) {
+		writeFile(t, path, content)
+		w.modTime = time.Time{}
+		w.check()
+	}
+
+	t.Run("Apply reloadable values", func(t *testing.T) {
+		touch("rateLimitRPS: 9\n")
+		assert.Len(t, reloaded, 1)
+		assert.Equal(t, 9, reloaded[0].RateLimitRPS)
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Keep config when the file is invalid", func(t *testing.T) {
+		touch("rateLimitRPS: 0\n")
+		assert.Len(t, reloaded, 1)
+		assert.ErrorContains(t, errs[0], "config reload rejected")
+	})
+
+	t.Run("Report values that need a restart", func(t *testing.T) {
+		touch("rateLimitRPS: 9\nseasonStandings: 5\n")
+		assert.Len(t, reloaded, 1)
+		assert.Equal(t, 100, w.current.SeasonStandings)
+		assert.ErrorContains(t, errs[1], "restart")
+	})
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Watcher reloads the config file when it changes. Only the rate limits are
+// applied to a running server; changes to other values are reported and need
+// a restart.
+type Watcher struct {
+	path     string
+	current  Config
+	modTime  time.Time
+	onReload func(Config)
+	onError  func(error)
+}
+
+// NewWatcher creates a Watcher for the file at path, starting from the loaded config current.
+// onReload receives the config with the new reloadable values; onError receives rejected reloads.
+func NewWatcher(path string, current Config, onReload func(Config), onError func(error)) *Watcher {
+	w := &Watcher{
+		path:     path,
+		current:  current,
+		onReload: onReload,
+		onError:  onError,
+	}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime = info.ModTime()
+	}
+	return w
+}
+
+// Run checks the file every interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the file if its modification time changed.
+// An invalid file keeps the current config.
+func (w *Watcher) check() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		w.onError(fmt.Errorf("watching config file: %w", err))
+		return
+	}
+	if info.ModTime().Equal(w.modTime) {
+		return
+	}
+	w.modTime = info.ModTime()
+
+	load := Load
+	if w.current.Dev {
+		load = LoadDev
+	}
+	next, err := load(w.path)
+	if err != nil {
+		w.onError(fmt.Errorf("config reload rejected: %w", err))
+		return
+	}
+
+	reloaded := w.current
+	reloaded.RateLimitRPS = next.RateLimitRPS
+	reloaded.RateLimitBurst = next.RateLimitBurst
+	reloaded.PublicRateLimitRPS = next.PublicRateLimitRPS
+	reloaded.APIKeyRateLimitRPS = next.APIKeyRateLimitRPS
+	reloaded.AdminRateLimitRPS = next.AdminRateLimitRPS
+
+	if !reflect.DeepEqual(reloaded, next) {
+		w.onError(errors.New("config file changed values that are not reloadable; restart to apply them"))
+	}
+	if !reflect.DeepEqual(reloaded, w.current) {
+		w.current = reloaded
+		w.onReload(reloaded)
+	}
+}
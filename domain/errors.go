@@ -0,0 +1,9 @@
+package dmn
+
+import "errors"
+
+// Errors that callers of repositories and services tell apart with errors.Is.
+var (
+	ErrMatchRecorded  = errors.New("match already recorded") // A match with the same ID was recorded before
+	ErrReplayNotFound = errors.New("replay not found")       // No replay has the given ID
+)
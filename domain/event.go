@@ -0,0 +1,55 @@
+package dmn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event represents a time-windowed special game mode.
+type Event struct {
+	ID          uuid.UUID          `bson:"_id"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description"`
+	Mode        string             `bson:"mode"`      // Game mode applied while the event runs
+	Modifiers   map[string]float64 `bson:"modifiers"` // Session option overrides, e.g. rewardMultiplier
+	StartsAt    time.Time          `bson:"startsAt"`
+	EndsAt      time.Time          `bson:"endsAt"`
+}
+
+// EventConfig holds parameters for creating an Event.
+type EventConfig struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	Mode        string
+	Modifiers   map[string]float64
+	StartsAt    time.Time
+	EndsAt      time.Time
+}
+
+// NewEvent creates a new Event with the provided configuration.
+func NewEvent(config EventConfig) (*Event, error) {
+	if config.Name == "" {
+		return nil, errors.New("event name is required")
+	}
+	if !config.EndsAt.After(config.StartsAt) {
+		return nil, errors.New("event must end after it starts")
+	}
+
+	return &Event{
+		ID:          config.ID,
+		Name:        config.Name,
+		Description: config.Description,
+		Mode:        config.Mode,
+		Modifiers:   config.Modifiers,
+		StartsAt:    config.StartsAt,
+		EndsAt:      config.EndsAt,
+	}, nil
+}
+
+// ActiveAt reports whether the event is running at the given time.
+func (e *Event) ActiveAt(t time.Time) bool {
+	return !t.Before(e.StartsAt) && t.Before(e.EndsAt)
+}
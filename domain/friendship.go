@@ -0,0 +1,83 @@
+package dmn
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Friendship statuses.
+const (
+	FriendshipPending  = "pending"  // Requested, waiting for the addressee to accept
+	FriendshipAccepted = "accepted" // Both users are friends
+)
+
+// Friendship represents a friend request between two users and, once accepted, their friendship.
+type Friendship struct {
+	ID          uuid.UUID `bson:"_id"`
+	RequesterID uuid.UUID `bson:"requesterID"`
+	AddresseeID uuid.UUID `bson:"addresseeID"`
+	Status      string    `bson:"status"`
+	CreatedAt   time.Time `bson:"createdAt"`
+	AcceptedAt  time.Time `bson:"acceptedAt,omitempty"`
+}
+
+// Friend is a friendship seen from one of its users.
+type Friend struct {
+	UserID   uuid.UUID
+	Username string
+	Status   string
+	Incoming bool // Whether the other user sent the request
+	Since    time.Time
+}
+
+// friendshipNamespace namespaces the name-based IDs of friendships.
+var friendshipNamespace = uuid.MustParse("6f1c2a8e-4b7d-4e0a-9c3f-2d5e8a1b7c40")
+
+// FriendshipID returns the ID of the friendship between two users, whoever requested it.
+// Deriving it from the pair lets the repository reject a second friendship between them.
+func FriendshipID(userID, otherID uuid.UUID) uuid.UUID {
+	if bytes.Compare(userID[:], otherID[:]) > 0 {
+		userID, otherID = otherID, userID
+	}
+	return uuid.NewSHA1(friendshipNamespace, append(userID[:], otherID[:]...))
+}
+
+// NewFriendRequest creates a pending friendship requested by requesterID.
+func NewFriendRequest(requesterID, addresseeID uuid.UUID) (*Friendship, error) {
+	if requesterID == addresseeID {
+		return nil, errors.New("cannot befriend yourself")
+	}
+
+	return &Friendship{
+		ID:          FriendshipID(requesterID, addresseeID),
+		RequesterID: requesterID,
+		AddresseeID: addresseeID,
+		Status:      FriendshipPending,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Accept accepts the pending request on behalf of userID, who must be its addressee.
+func (f *Friendship) Accept(userID uuid.UUID) error {
+	if f.Status != FriendshipPending {
+		return errors.New("friend request already accepted")
+	}
+	if f.AddresseeID != userID {
+		return errors.New("only the addressee can accept a friend request")
+	}
+
+	f.Status = FriendshipAccepted
+	f.AcceptedAt = time.Now()
+	return nil
+}
+
+// Other returns the user of the friendship that is not userID.
+func (f *Friendship) Other(userID uuid.UUID) uuid.UUID {
+	if f.RequesterID == userID {
+		return f.AddresseeID
+	}
+	return f.RequesterID
+}
@@ -0,0 +1,11 @@
+package dmn
+
+import "github.com/google/uuid"
+
+// LeaderboardEntry represents a player's position on the leaderboard.
+type LeaderboardEntry struct {
+	Rank     int
+	PlayerID uuid.UUID
+	Username string
+	Rating   int
+}
@@ -0,0 +1,77 @@
+package dmn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatchResult records the outcome of a finished game.
+// Its ID is the ID of the game session.
+type MatchResult struct {
+	ID         uuid.UUID     `bson:"_id"`
+	PlayerIDs  []uuid.UUID   `bson:"playerIDs"`
+	Players    []MatchPlayer `bson:"players"`
+	MazeWidth  int           `bson:"mazeWidth"`
+	MazeHeight int           `bson:"mazeHeight"`
+	StartedAt  time.Time     `bson:"startedAt"`
+	EndedAt    time.Time     `bson:"endedAt"`
+
+	// PlacementsApplied is set once the players' placement matches were consumed.
+	// A retried report finishes the step when it is still unset.
+	PlacementsApplied bool `bson:"placementsApplied"`
+}
+
+// MatchPlayer holds a player's score and rating change in a match.
+type MatchPlayer struct {
+	PlayerID     uuid.UUID `bson:"playerID"`
+	Score        int       `bson:"score"`
+	RatingBefore int       `bson:"ratingBefore"`
+	RatingAfter  int       `bson:"ratingAfter"`
+	Placement    bool      `bson:"placement"` // Whether the match was one of the player's placement matches
+}
+
+// HeadToHead summarizes the matches two players played against each other.
+type HeadToHead struct {
+	PlayerID   uuid.UUID
+	OpponentID uuid.UUID
+	Matches    int
+	Wins       int
+	Losses     int
+	Draws      int
+}
+
+// Validate checks that the match has players, each listed once, and ends after it starts.
+func (m *MatchResult) Validate() error {
+	if len(m.Players) == 0 {
+		return errors.New("match has no players")
+	}
+	if m.EndedAt.Before(m.StartedAt) {
+		return errors.New("match ended before it started")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(m.Players))
+	for _, p := range m.Players {
+		if seen[p.PlayerID] {
+			return errors.New("player listed twice")
+		}
+		seen[p.PlayerID] = true
+	}
+	return nil
+}
+
+// Duration returns how long the match lasted.
+func (m *MatchResult) Duration() time.Duration {
+	return m.EndedAt.Sub(m.StartedAt)
+}
+
+// Player returns the result of the given player, or nil if they did not play.
+func (m *MatchResult) Player(playerID uuid.UUID) *MatchPlayer {
+	for i := range m.Players {
+		if m.Players[i].PlayerID == playerID {
+			return &m.Players[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package dmn
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Replay represents the recorded timeline of a finished match.
+type Replay struct {
+	ID        uuid.UUID     `bson:"_id"`
+	PlayerIDs []uuid.UUID   `bson:"playerIDs"`
+	StartedAt time.Time     `bson:"startedAt"`
+	EndedAt   time.Time     `bson:"endedAt"`
+	Frames    []ReplayFrame `bson:"frames,omitempty"` // Set when saving; loaded replays stream frames instead
+}
+
+// ReplayFrame holds a single versioned game state and the action that produced it.
+type ReplayFrame struct {
+	Version  int64     `bson:"version"`
+	At       time.Time `bson:"at"`
+	PlayerID uuid.UUID `bson:"playerID"` // Player whose action produced the state; zero for server updates
+	Action   []byte    `bson:"action,omitempty"`
+	State    []byte    `bson:"state"`
+}
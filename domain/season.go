@@ -0,0 +1,36 @@
+package dmn
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Season is a ranked period that ends with a snapshot of the leaderboard and a
+// soft reset of every rating.
+type Season struct {
+	Number    int              `bson:"_id"`
+	StartedAt time.Time        `bson:"startedAt"`
+	EndedAt   time.Time        `bson:"endedAt,omitempty"`
+	Standings []SeasonStanding `bson:"standings,omitempty"` // Top of the leaderboard when the season ended
+}
+
+// SeasonStanding is a player's final position in an ended season.
+type SeasonStanding struct {
+	Rank     int       `bson:"rank"`
+	PlayerID uuid.UUID `bson:"playerID"`
+	Username string    `bson:"username"`
+	Rating   int       `bson:"rating"`
+}
+
+// SeasonReset describes how ratings are reset when a season ends.
+type SeasonReset struct {
+	Mean             int     // Rating every rating moves toward
+	Keep             float64 // Fraction of the distance to Mean that is kept, between 0 and 1
+	PlacementMatches int     // Matches of the new season flagged as placements for every player
+}
+
+// Ended reports whether the season is over.
+func (s *Season) Ended() bool {
+	return !s.EndedAt.IsZero()
+}
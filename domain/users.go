@@ -19,6 +19,13 @@ const (
 	defautlRating = 1400
 )
 
+// Roles granted to token bearers.
+const (
+	RolePlayer  = "player"  // Plays matches; granted to every registered user
+	RoleAdmin   = "admin"   // Operates the service through admin endpoints
+	RoleService = "service" // Another backend service; never granted to users
+)
+
 var (
 	usernameRegex = regexp.MustCompile(usernamePattern)
 )
@@ -28,7 +35,45 @@ type User struct {
 	ID           uuid.UUID `bson:"_id"`
 	Username     string    `bson:"username"`
 	PasswordHash string    `bson:"passwordHash"`
-	Rating       int       `bson:"rating"`
+	Roles        []string  `bson:"roles"`
+
+	// Rating is set by Save only when the user is created; afterwards it is changed
+	// by the game backend and season resets, which a saved profile must not undo.
+	Rating int `bson:"rating"`
+
+	// PlacementMatchesLeft counts the remaining placement matches of the season.
+	// Save only sets it when the user is created; afterwards it is changed by
+	// season resets and recorded matches.
+	PlacementMatchesLeft int `bson:"placementMatchesLeft"`
+
+	// ResetSeason is the number of the last season whose end reset the rating.
+	// It is only changed by season resets, never by Save.
+	ResetSeason int `bson:"resetSeason"`
+
+	// LastPlacementMatch is the last match that consumed a placement match, so that a
+	// retried report of it does not consume another. It is never changed by Save.
+	LastPlacementMatch uuid.UUID `bson:"lastPlacementMatch"`
+}
+
+// UserFilter narrows the users listed by a user repository. Zero values do not filter.
+type UserFilter struct {
+	MinRating      *int   // Lowest rating included
+	MaxRating      *int   // Highest rating included
+	UsernamePrefix string // Case sensitive prefix of the username
+}
+
+// UserSort orders the users listed by a user repository.
+type UserSort string
+
+const (
+	UserSortRatingDesc UserSort = "-rating"  // Highest rating first, ties broken by username
+	UserSortRatingAsc  UserSort = "rating"   // Lowest rating first, ties broken by username
+	UserSortUsername   UserSort = "username" // Alphabetical by username
+)
+
+// Valid reports whether the sort order is known.
+func (s UserSort) Valid() bool {
+	return s == UserSortRatingDesc || s == UserSortRatingAsc || s == UserSortUsername
 }
 
 // UserConfig holds parameters for creating a User with an existing password hash.
@@ -58,6 +103,7 @@ func NewUser(config UserConfig) (*User, error) {
 		Username:     config.Username,
 		PasswordHash: passwordHash,
 		Rating:       defautlRating,
+		Roles:        []string{RolePlayer},
 	}, nil
 }
 
@@ -67,6 +113,64 @@ func (u *User) VerifyPassword(password string) bool {
 	return err == nil
 }
 
+// ChangePassword replaces the password after verifying the current one.
+func (u *User) ChangePassword(oldPassword, newPassword string) error {
+	if !u.VerifyPassword(oldPassword) {
+		return errors.New("invalid password")
+	}
+
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = passwordHash
+	return nil
+}
+
+// Rename changes the username after validating it.
+func (u *User) Rename(username string) error {
+	if err := validateUsername(username); err != nil {
+		return err
+	}
+	u.Username = username
+	return nil
+}
+
+// GrantedRoles returns the roles of the user.
+// Users stored before roles were introduced are players.
+func (u *User) GrantedRoles() []string {
+	if len(u.Roles) == 0 {
+		return []string{RolePlayer}
+	}
+	return u.Roles
+}
+
+// HasRole reports whether the user has been granted the role.
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.GrantedRoles() {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GrantRole adds the role to the user; granting a role the user has is a no-op.
+func (u *User) GrantRole(role string) error {
+	if role != RolePlayer && role != RoleAdmin {
+		return errors.New("role cannot be granted to users")
+	}
+	if u.HasRole(role) {
+		return nil
+	}
+	u.Roles = append(u.GrantedRoles(), role)
+	return nil
+}
+
 // validateUsername validates the username.
 func validateUsername(username string) error {
 	if len(username) < minUsernameLength {
@@ -0,0 +1,85 @@
+package contentfilter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordlist(t *testing.T) {
+	ctx := context.Background()
+	filter := NewWordlist([]string{"darn", "Heck"})
+
+	t.Run("Allow clean text", func(t *testing.T) {
+		allowed, err := filter.Allowed(ctx, "friendly_gopher")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Reject blocked word ignoring case and separators", func(t *testing.T) {
+		for _, text := range []string{"darn", "xX_DaRn_Xx", "h_e_c_k"} {
+			allowed, err := filter.Allowed(ctx, text)
+			assert.NoError(t, err)
+			assert.False(t, allowed, text)
+		}
+	})
+
+	t.Run("Reject leetspeak substitutions", func(t *testing.T) {
+		allowed, err := filter.Allowed(ctx, "h3ck_y34h")
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("Allow blocked words inside allowed words", func(t *testing.T) {
+		filter := NewWordlist([]string{"cunt", "!Scunthorpe"})
+		allowed, err := filter.Allowed(ctx, "Scunthorpe_United")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, err = filter.Allowed(ctx, "scunthorpe_cunt")
+		assert.NoError(t, err)
+		assert.False(t, allowed, "an occurrence outside the allowed word is still blocked")
+	})
+
+	t.Run("Default list loads", func(t *testing.T) {
+		for _, text := range []string{"gopher", "Scunthorpe", "pussycat_99", "Matsushita"} {
+			allowed, err := NewDefaultWordlist().Allowed(ctx, text)
+			assert.NoError(t, err)
+			assert.True(t, allowed, text)
+		}
+	})
+}
+
+func TestRemote(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req remoteRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(&remoteResponse{Allowed: req.Text != "blocked"})
+	}))
+	defer server.Close()
+
+	filter := NewChain(NewWordlist([]string{"darn"}), NewRemote(server.URL, time.Second))
+
+	allowed, err := filter.Allowed(ctx, "gopher")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = filter.Allowed(ctx, "blocked")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = filter.Allowed(ctx, "darn")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = filter.Allowed(canceled, "gopher")
+	assert.ErrorIs(t, err, context.Canceled)
+}
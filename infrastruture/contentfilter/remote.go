@@ -0,0 +1,90 @@
+package contentfilter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+)
+
+// Remote delegates decisions to an external moderation service.
+// The service receives POST {"text": "..."} and answers {"allowed": true|false}.
+// Implements i.ContentFilter.
+type Remote struct {
+	url    string
+	client *http.Client
+}
+
+// NewRemote creates a filter backed by the moderation service at url.
+func NewRemote(url string, timeout time.Duration) i.ContentFilter {
+	return &Remote{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type remoteRequest struct {
+	Text string `json:"text"`
+}
+
+type remoteResponse struct {
+	Allowed bool `json:"allowed"`
+}
+
+// Allowed implements i.ContentFilter.
+func (r *Remote) Allowed(ctx context.Context, text string) (bool, error) {
+	body, err := json.Marshal(&remoteRequest{Text: text})
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("content filter service responded with %d", res.StatusCode)
+	}
+
+	var decision remoteResponse
+	if err := json.NewDecoder(res.Body).Decode(&decision); err != nil {
+		return false, err
+	}
+	return decision.Allowed, nil
+}
+
+// Chain allows text only if every filter allows it, stopping at the first rejection.
+// Implements i.ContentFilter.
+type Chain struct {
+	filters []i.ContentFilter
+}
+
+// NewChain combines filters; cheaper filters should come first.
+func NewChain(filters ...i.ContentFilter) i.ContentFilter {
+	return &Chain{
+		filters: filters,
+	}
+}
+
+// Allowed implements i.ContentFilter.
+func (c *Chain) Allowed(ctx context.Context, text string) (bool, error) {
+	for _, f := range c.filters {
+		allowed, err := f.Allowed(ctx, text)
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
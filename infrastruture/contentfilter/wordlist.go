@@ -0,0 +1,131 @@
+// Package contentfilter provides implementations of i.ContentFilter.
+package contentfilter
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"os"
+	"strings"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+)
+
+//go:embed default_words.txt
+var defaultWords string
+
+// leetReplacer maps common character substitutions back to letters.
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// allowPrefix marks a word list entry as an allowed word rather than a blocked one.
+const allowPrefix = "!"
+
+// Wordlist rejects text containing any of its blocked words, unless every
+// occurrence lies within one of its allowed words, e.g. "cunt" in "Scunthorpe".
+// Implements i.ContentFilter.
+type Wordlist struct {
+	words   []string
+	allowed []string
+}
+
+// NewWordlist creates a filter blocking the given words.
+// Words prefixed with "!" are allowed words that blocked words may occur in.
+func NewWordlist(words []string) i.ContentFilter {
+	blocked := make([]string, 0, len(words))
+	allowed := make([]string, 0)
+	for _, w := range words {
+		list := &blocked
+		if after, ok := strings.CutPrefix(w, allowPrefix); ok {
+			w, list = after, &allowed
+		}
+		if w = normalize(w); w != "" {
+			*list = append(*list, w)
+		}
+	}
+	return &Wordlist{
+		words:   blocked,
+		allowed: allowed,
+	}
+}
+
+// NewDefaultWordlist creates a filter using the built-in word list.
+func NewDefaultWordlist() i.ContentFilter {
+	return NewWordlist(strings.Split(defaultWords, "\n"))
+}
+
+// LoadWordlist creates a filter from a file with one blocked word per line.
+// Lines starting with ! list allowed words, as for NewWordlist.
+// Empty lines and lines starting with # are ignored.
+func LoadWordlist(path string) (i.ContentFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewWordlist(words), nil
+}
+
+// Allowed implements i.ContentFilter.
+func (w *Wordlist) Allowed(_ context.Context, text string) (bool, error) {
+	normalized := normalize(text)
+	for _, word := range w.words {
+		for start := range occurrences(normalized, word) {
+			if !w.excepted(normalized, start, start+len(word)) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// excepted reports whether text[start:end] lies within an occurrence of an allowed word.
+func (w *Wordlist) excepted(text string, start, end int) bool {
+	for _, allowed := range w.allowed {
+		for from := range occurrences(text, allowed) {
+			if from <= start && end <= from+len(allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// occurrences yields the start of every, possibly overlapping, occurrence of word in text.
+func occurrences(text, word string) func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for offset := 0; offset <= len(text)-len(word); {
+			idx := strings.Index(text[offset:], word)
+			if idx < 0 || !yield(offset+idx) {
+				return
+			}
+			offset += idx + 1
+		}
+	}
+}
+
+// normalize lowercases text, undoes common substitutions, and drops everything but letters.
+func normalize(text string) string {
+	text = leetReplacer.Replace(strings.ToLower(text))
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, text)
+}
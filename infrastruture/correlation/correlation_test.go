@@ -0,0 +1,32 @@
+package correlation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var sent metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	interceptor := UnaryClientInterceptor()
+
+	t.Run("Send the ID carried by the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(WithID(context.Background(), "req-1"))
+		defer cancel()
+
+		assert.NoError(t, interceptor(ctx, "/svc/Method", nil, nil, nil, invoker))
+		assert.Equal(t, []string{"req-1"}, sent.Get(MetadataKey))
+	})
+
+	t.Run("Send nothing without an ID", func(t *testing.T) {
+		assert.NoError(t, interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker))
+		assert.Empty(t, sent.Get(MetadataKey))
+	})
+}
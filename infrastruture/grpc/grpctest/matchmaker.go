@@ -0,0 +1,69 @@
+package grpctest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Ticket is a player waiting in an InMemoryMatchmaker queue.
+type Ticket struct {
+	PlayerID uuid.UUID
+	Rating   int
+	Latency  uint
+}
+
+// InMemoryMatchmaker is an in-memory i.Matchmaker. Players are matched in
+// arrival order, ignoring rating and latency, and placed in a session of the
+// InMemorySessionManager once matchSize players are queued.
+type InMemoryMatchmaker struct {
+	matchSize  int
+	sessions   *InMemorySessionManager
+	pubKey     []byte
+	socketAddr string
+	queue      []Ticket
+	mu         sync.Mutex
+}
+
+// NewInMemoryMatchmaker creates an InMemoryMatchmaker that places matched players in
+// sessions served at socketAddr with the given public key.
+func NewInMemoryMatchmaker(matchSize int, sessions *InMemorySessionManager, pubKey []byte, socketAddr string) *InMemoryMatchmaker {
+	return &InMemoryMatchmaker{
+		matchSize:  max(matchSize, 1),
+		sessions:   sessions,
+		pubKey:     pubKey,
+		socketAddr: socketAddr,
+	}
+}
+
+// Match implements i.Matchmaker. Queuing a player twice keeps the first ticket.
+func (m *InMemoryMatchmaker) Match(_ context.Context, id uuid.UUID, rating int, latency uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if slices.ContainsFunc(m.queue, func(t Ticket) bool { return t.PlayerID == id }) {
+		return nil
+	}
+	m.queue = append(m.queue, Ticket{PlayerID: id, Rating: rating, Latency: latency})
+
+	if len(m.queue) < m.matchSize {
+		return nil
+	}
+	playerIDs := make([]uuid.UUID, 0, m.matchSize)
+	for _, t := range m.queue[:m.matchSize] {
+		playerIDs = append(playerIDs, t.PlayerID)
+	}
+	m.queue = slices.Delete(m.queue, 0, m.matchSize)
+	m.sessions.Place(m.pubKey, m.socketAddr, playerIDs...)
+	return nil
+}
+
+// Queued returns the players waiting for a match, in arrival order.
+func (m *InMemoryMatchmaker) Queued() []Ticket {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.queue)
+}
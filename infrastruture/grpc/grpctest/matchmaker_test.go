@@ -0,0 +1,43 @@
+package grpctest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryMatchmaker(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewInMemorySessionManager()
+	mm := NewInMemoryMatchmaker(2, sessions, []byte("key"), "127.0.0.1:9000")
+	first, second := uuid.New(), uuid.New()
+
+	t.Run("Queue until the match is full", func(t *testing.T) {
+		assert.NoError(t, mm.Match(ctx, first, 1500, 40))
+		assert.NoError(t, mm.Match(ctx, first, 1500, 40))
+		assert.Equal(t, []Ticket{{PlayerID: first, Rating: 1500, Latency: 40}}, mm.Queued())
+
+		_, _, err := sessions.SessionInfo(ctx, first)
+		assert.Error(t, err)
+	})
+
+	t.Run("Place matched players in a session", func(t *testing.T) {
+		assert.NoError(t, mm.Match(ctx, second, 1400, 60))
+		assert.Empty(t, mm.Queued())
+
+		for _, id := range []uuid.UUID{first, second} {
+			pubKey, addr, err := sessions.SessionInfo(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("key"), pubKey)
+			assert.Equal(t, "127.0.0.1:9000", addr)
+		}
+	})
+
+	t.Run("End sessions", func(t *testing.T) {
+		sessions.End(first)
+		_, _, err := sessions.SessionInfo(ctx, first)
+		assert.Error(t, err)
+	})
+}
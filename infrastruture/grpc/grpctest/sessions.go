@@ -0,0 +1,61 @@
+// Package grpctest provides in-memory stand-ins for the matchmaking and session
+// manager services, for tests and local development without the game backend.
+package grpctest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type session struct {
+	pubKey     []byte
+	socketAddr string
+}
+
+// InMemorySessionManager is an in-memory i.GameSessionManager serving the sessions
+// players were placed in with Place.
+type InMemorySessionManager struct {
+	sessions map[uuid.UUID]session
+	mu       sync.RWMutex
+}
+
+// NewInMemorySessionManager creates an InMemorySessionManager without sessions.
+func NewInMemorySessionManager() *InMemorySessionManager {
+	return &InMemorySessionManager{sessions: make(map[uuid.UUID]session)}
+}
+
+// Place puts the players in a session served at socketAddr with the given public key.
+func (s *InMemorySessionManager) Place(pubKey []byte, socketAddr string, playerIDs ...uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range playerIDs {
+		s.sessions[id] = session{pubKey: slices.Clone(pubKey), socketAddr: socketAddr}
+	}
+}
+
+// End removes the players from their sessions.
+func (s *InMemorySessionManager) End(playerIDs ...uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range playerIDs {
+		delete(s.sessions, id)
+	}
+}
+
+// SessionInfo implements i.GameSessionManager.
+func (s *InMemorySessionManager) SessionInfo(_ context.Context, playerID uuid.UUID) ([]byte, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[playerID]
+	if !ok {
+		return nil, "", errors.New("session not found")
+	}
+	return slices.Clone(sess.pubKey), sess.socketAddr, nil
+}
@@ -0,0 +1,196 @@
+package grpctls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca testCA) issue(t *testing.T, serial int64, dnsName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// handshake runs a TLS handshake between the client credentials and a server
+// requiring client certificates, returning the client certificate the server saw.
+func handshake(t *testing.T, ca testCA, serverName string, cfg Config) (*x509.Certificate, error) {
+	serverCertPEM, serverKeyPEM := ca.issue(t, 100, "session.internal", x509.ExtKeyUsageServerAuth)
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	assert.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	creds, err := NewClientCredentials(cfg)
+	assert.NoError(t, err)
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	peerCert := make(chan *x509.Certificate, 1)
+	go func() {
+		server := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+			NextProtos:   []string{"h2"},
+		})
+		if server.Handshake() != nil {
+			peerCert <- nil
+			return
+		}
+		peerCert <- server.ConnectionState().PeerCertificates[0]
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err = creds.ClientHandshake(ctx, serverName, clientConn)
+	if err != nil {
+		return nil, err
+	}
+	return <-peerCert, nil
+}
+
+func TestNewClientCredentials(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", ca.pem)
+	certPEM, keyPEM := ca.issue(t, 2, "api", x509.ExtKeyUsageClientAuth)
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", keyPEM)
+
+	t.Run("mutual TLS with a verified server", func(t *testing.T) {
+		cert, err := handshake(t, ca, "session.internal:50051", Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+		assert.NoError(t, err)
+		if assert.NotNil(t, cert) {
+			assert.Equal(t, int64(2), cert.SerialNumber.Int64())
+		}
+	})
+
+	t.Run("rejects a server name mismatch", func(t *testing.T) {
+		_, err := handshake(t, ca, "other.internal:50051", Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+		assert.Error(t, err)
+	})
+
+	t.Run("server name override", func(t *testing.T) {
+		_, err := handshake(t, ca, "10.0.0.5:50051", Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "session.internal"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects a server from another CA", func(t *testing.T) {
+		otherCAFile := writeFile(t, t.TempDir(), "ca.pem", newTestCA(t).pem)
+		_, err := handshake(t, ca, "session.internal:50051", Config{CAFile: otherCAFile, CertFile: certFile, KeyFile: keyFile})
+		assert.Error(t, err)
+	})
+
+	t.Run("certificate without key", func(t *testing.T) {
+		_, err := NewClientCredentials(Config{CAFile: caFile, CertFile: certFile})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing CA file", func(t *testing.T) {
+		_, err := NewClientCredentials(Config{CAFile: filepath.Join(dir, "missing.pem")})
+		assert.Error(t, err)
+	})
+}
+
+func TestReloader(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile := writeFile(t, dir, "ca.pem", ca.pem)
+	certPEM, keyPEM := ca.issue(t, 2, "api", x509.ExtKeyUsageClientAuth)
+	certFile := writeFile(t, dir, "client.pem", certPEM)
+	keyFile := writeFile(t, dir, "client-key.pem", keyPEM)
+
+	r := &reloader{cfg: Config{CAFile: caFile, CertFile: certFile, KeyFile: keyFile}}
+	assert.NoError(t, r.load())
+
+	serial := func() int64 {
+		cert, err := r.clientCertificate(nil)
+		assert.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		assert.NoError(t, err)
+		return leaf.SerialNumber.Int64()
+	}
+
+	t.Run("picks up a rotated certificate", func(t *testing.T) {
+		certPEM, keyPEM := ca.issue(t, 3, "api", x509.ExtKeyUsageClientAuth)
+		writeFile(t, dir, "client.pem", certPEM)
+		writeFile(t, dir, "client-key.pem", keyPEM)
+		future := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(certFile, future, future))
+		assert.NoError(t, os.Chtimes(keyFile, future, future))
+
+		r.lastCheck = time.Time{}
+		assert.Equal(t, int64(3), serial())
+	})
+
+	t.Run("keeps the previous certificate when the new files are invalid", func(t *testing.T) {
+		writeFile(t, dir, "client.pem", []byte("not a certificate"))
+		future := time.Now().Add(2 * time.Minute)
+		assert.NoError(t, os.Chtimes(certFile, future, future))
+
+		r.lastCheck = time.Time{}
+		assert.Equal(t, int64(3), serial())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/beka-birhanu/vinom-api/infrastruture/correlation"
 	"github.com/beka-birhanu/vinom-api/service/i"
 	general_i "github.com/beka-birhanu/vinom-common/interfaces/general"
 	"github.com/google/uuid"
@@ -37,13 +38,14 @@ func (c *clientAdapter) Match(ctx context.Context, id uuid.UUID, rating int, lat
 		Latency: int32(latency),
 	}
 
-	c.logger.Info(fmt.Sprintf("sending match request for player: %s", id))
+	requestID := correlation.ID(ctx)
+	c.logger.Info(fmt.Sprintf("sending match request for player: %s (request %s)", id, requestID))
 	_, err := c.client.Match(timeoutCtx, request)
 	if err != nil {
-		c.logger.Error(fmt.Sprintf("match request failed for player %s: %s", id, err))
+		c.logger.Error(fmt.Sprintf("match request failed for player %s (request %s): %s", id, requestID, err))
 		return err
 	}
 
-	c.logger.Info(fmt.Sprintf("match request success for player %s", id))
+	c.logger.Info(fmt.Sprintf("match request success for player %s (request %s)", id, requestID))
 	return nil
 }
@@ -0,0 +1,206 @@
+// Package resilience adds retries and a circuit breaker to gRPC client calls.
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds the retry and circuit breaker settings for a connection.
+type Config struct {
+	MaxAttempts      int           // Attempts per call, including the first
+	BaseBackoff      time.Duration // Backoff before the first retry; doubles on each retry
+	MaxBackoff       time.Duration // Upper bound for a single backoff
+	AttemptTimeout   time.Duration // Deadline for each attempt; zero relies on the caller's deadline
+	FailureThreshold int           // Consecutive failed calls that trip the breaker
+	Cooldown         time.Duration // How long the breaker stays open before allowing a trial call
+}
+
+// UnavailableError is returned when the service is unreachable or the breaker is open.
+// Implements i.Unavailable.
+type UnavailableError struct {
+	retryAfter time.Duration
+	cause      error
+}
+
+// Error implements error.
+func (e *UnavailableError) Error() string {
+	if e.cause == nil {
+		return fmt.Sprintf("service unavailable, retry after %s", e.retryAfter)
+	}
+	return fmt.Sprintf("service unavailable, retry after %s: %v", e.retryAfter, e.cause)
+}
+
+// Unwrap returns the error of the last attempt, if any.
+func (e *UnavailableError) Unwrap() error {
+	return e.cause
+}
+
+// RetryAfter implements i.Unavailable.
+func (e *UnavailableError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// UnaryClientInterceptor retries calls failing with UNAVAILABLE using exponential
+// backoff and stops calling the service for a cooldown after repeated failures.
+// One breaker is shared by every call made through the interceptor.
+func UnaryClientInterceptor(cfg Config) grpc.UnaryClientInterceptor {
+	b := &breaker{
+		threshold: cfg.FailureThreshold,
+		cooldown:  cfg.Cooldown,
+		now:       time.Now,
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if wait, ok := b.allow(); !ok {
+			return &UnavailableError{retryAfter: wait}
+		}
+
+		var err error
+		for attempt := 0; attempt < max(cfg.MaxAttempts, 1); attempt++ {
+			if attempt > 0 {
+				if !sleep(ctx, backoff(cfg, attempt)) {
+					break
+				}
+			}
+
+			err = invokeAttempt(ctx, cfg.AttemptTimeout, method, req, reply, cc, invoker, opts...)
+			if !retryable(err) {
+				break
+			}
+		}
+
+		switch {
+		case failed(err):
+			b.failure()
+		case status.Code(err) == codes.Canceled:
+			// The caller gave up, which says nothing about the service's health.
+			b.release()
+		default:
+			b.success()
+		}
+
+		if retryable(err) {
+			return &UnavailableError{retryAfter: cfg.MaxBackoff, cause: err}
+		}
+		return err
+	}
+}
+
+func invokeAttempt(ctx context.Context, timeout time.Duration, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// retryable reports whether the call may succeed if attempted again.
+func retryable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// failed reports whether the error indicates an unhealthy service, as opposed to
+// an application level error such as NOT_FOUND.
+func failed(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the jittered delay before the given retry attempt.
+func backoff(cfg Config, attempt int) time.Duration {
+	delay := cfg.BaseBackoff << (attempt - 1)
+	if delay <= 0 || delay > cfg.MaxBackoff {
+		delay = cfg.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Full jitter between half and the whole delay.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// sleep waits for d or until ctx is done, reporting whether the full delay elapsed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// breaker is a consecutive-failure circuit breaker.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool // A half-open trial call is in flight
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// allow reports whether a call may proceed and, if not, how long to wait.
+func (b *breaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return 0, true
+	}
+
+	now := b.now()
+	if now.Before(b.openUntil) {
+		return b.openUntil.Sub(now), false
+	}
+
+	// Half-open: let a single trial call through.
+	if b.trial {
+		return b.cooldown, false
+	}
+	b.trial = true
+	return 0, true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+// release ends a trial call without counting it as a success or a failure.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,114 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scriptedInvoker returns the given errors in order, then succeeds.
+func scriptedInvoker(calls *int, errs ...error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "down")
+	cfg := Config{
+		MaxAttempts:      3,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		FailureThreshold: 2,
+		Cooldown:         time.Hour,
+	}
+
+	t.Run("Retry unavailable until success", func(t *testing.T) {
+		calls := 0
+		interceptor := UnaryClientInterceptor(cfg)
+		err := interceptor(context.Background(), "/m", nil, nil, nil, scriptedInvoker(&calls, unavailable, unavailable))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Do not retry application errors", func(t *testing.T) {
+		calls := 0
+		notFound := status.Error(codes.NotFound, "no session")
+		interceptor := UnaryClientInterceptor(cfg)
+		err := interceptor(context.Background(), "/m", nil, nil, nil, scriptedInvoker(&calls, notFound))
+
+		assert.Equal(t, notFound, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Report exhausted retries as unavailable", func(t *testing.T) {
+		calls := 0
+		interceptor := UnaryClientInterceptor(cfg)
+		err := interceptor(context.Background(), "/m", nil, nil, nil, scriptedInvoker(&calls, unavailable, unavailable, unavailable))
+
+		var u i.Unavailable
+		assert.True(t, errors.As(err, &u))
+		assert.Equal(t, codes.Unavailable, status.Code(errors.Unwrap(err)))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Open breaker after consecutive failures", func(t *testing.T) {
+		calls := 0
+		interceptor := UnaryClientInterceptor(cfg)
+		invoker := scriptedInvoker(&calls, unavailable, unavailable, unavailable, unavailable, unavailable, unavailable)
+
+		_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+		_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+		err := interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+
+		var u i.Unavailable
+		assert.True(t, errors.As(err, &u))
+		assert.Greater(t, u.RetryAfter(), time.Duration(0))
+		assert.Equal(t, 6, calls, "open breaker must not reach the service")
+	})
+
+	t.Run("Keep counting failures across canceled calls", func(t *testing.T) {
+		calls := 0
+		interceptor := UnaryClientInterceptor(cfg)
+		canceled := status.Error(codes.Canceled, "client went away")
+		invoker := scriptedInvoker(&calls, unavailable, unavailable, unavailable, canceled, unavailable, unavailable, unavailable)
+
+		_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+		assert.Equal(t, canceled, interceptor(context.Background(), "/m", nil, nil, nil, invoker))
+		_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+		_ = interceptor(context.Background(), "/m", nil, nil, nil, invoker)
+
+		assert.Equal(t, 7, calls, "breaker must open after the second failed call")
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := &breaker{threshold: 1, cooldown: time.Second, now: func() time.Time { return now }}
+
+	b.failure()
+	_, ok := b.allow()
+	assert.False(t, ok)
+
+	now = now.Add(2 * time.Second)
+	_, ok = b.allow()
+	assert.True(t, ok, "half-open breaker allows a trial call")
+	_, ok = b.allow()
+	assert.False(t, ok, "only one trial call at a time")
+
+	b.success()
+	_, ok = b.allow()
+	assert.True(t, ok)
+}
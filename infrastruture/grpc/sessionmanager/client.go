@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/beka-birhanu/vinom-api/infrastruture/correlation"
 	"github.com/beka-birhanu/vinom-api/service/i"
 	general_i "github.com/beka-birhanu/vinom-common/interfaces/general"
 	"github.com/google/uuid"
@@ -35,13 +36,14 @@ func (c *clientAdapter) SessionInfo(ctx context.Context, id uuid.UUID) ([]byte,
 		PlayerID: id.String(),
 	}
 
-	c.logger.Info(fmt.Sprintf("sending session info request for player: %s", id))
+	requestID := correlation.ID(ctx)
+	c.logger.Info(fmt.Sprintf("sending session info request for player: %s (request %s)", id, requestID))
 	res, err := c.client.SessionInfo(timeoutCtx, request)
 	if err != nil {
-		c.logger.Error(fmt.Sprintf("session info request failed for player %s: %s", id, err))
+		c.logger.Error(fmt.Sprintf("session info request failed for player %s (request %s): %s", id, requestID, err))
 		return nil, "", err
 	}
 
-	c.logger.Info(fmt.Sprintf("session info request success for player %s", id))
+	c.logger.Info(fmt.Sprintf("session info request success for player %s (request %s)", id, requestID))
 	return []byte(res.GetServerPubKey()), res.GetServerAddr(), nil
 }
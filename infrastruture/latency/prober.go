@@ -0,0 +1,125 @@
+// Package latency measures player round trip times with a two step echo.
+package latency
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+)
+
+const (
+	// probeTTL bounds how long a started probe may stay open.
+	probeTTL = 5 * time.Second
+	// estimateTTL is how long a measured latency is trusted without new samples.
+	estimateTTL = 10 * time.Minute
+	// smoothing is the weight a new sample gets in the moving average.
+	smoothing = 0.3
+)
+
+type probe struct {
+	nonce     string
+	startedAt time.Time
+}
+
+type estimate struct {
+	rtt        time.Duration
+	measuredAt time.Time
+}
+
+// Prober is an in-memory i.LatencyProber. A round trip is the time between
+// the server issuing a nonce and receiving it back, so a client can not report
+// a latency lower than its real one.
+type Prober struct {
+	probes    map[uuid.UUID]probe
+	estimates map[uuid.UUID]estimate
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewProber creates an empty Prober.
+func NewProber() i.LatencyProber {
+	return &Prober{
+		probes:    make(map[uuid.UUID]probe),
+		estimates: make(map[uuid.UUID]estimate),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Start implements i.LatencyProber.
+func (p *Prober) Start(playerID uuid.UUID) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("unexpected error: " + err.Error())
+	}
+	nonce := hex.EncodeToString(raw)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.sweep(now)
+	p.probes[playerID] = probe{nonce: nonce, startedAt: now}
+	return nonce, nil
+}
+
+// Finish implements i.LatencyProber.
+func (p *Prober) Finish(playerID uuid.UUID, nonce string) (time.Duration, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	pr, ok := p.probes[playerID]
+	if !ok || pr.nonce != nonce {
+		return 0, errors.New("probe not found")
+	}
+	delete(p.probes, playerID)
+
+	rtt := now.Sub(pr.startedAt)
+	if rtt > probeTTL {
+		return 0, errors.New("probe expired")
+	}
+
+	if e, ok := p.estimates[playerID]; ok && now.Sub(e.measuredAt) <= estimateTTL {
+		rtt = time.Duration(smoothing*float64(rtt) + (1-smoothing)*float64(e.rtt))
+	}
+	p.estimates[playerID] = estimate{rtt: rtt, measuredAt: now}
+	return rtt, nil
+}
+
+// Latency implements i.LatencyProber.
+func (p *Prober) Latency(playerID uuid.UUID) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, ok := p.estimates[playerID]
+	if !ok || p.now().Sub(e.measuredAt) > estimateTTL {
+		return 0, false
+	}
+	return e.rtt, true
+}
+
+// sweep evicts expired probes and estimates, at most once per probeTTL.
+func (p *Prober) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < probeTTL {
+		return
+	}
+
+	for id, pr := range p.probes {
+		if now.Sub(pr.startedAt) > probeTTL {
+			delete(p.probes, id)
+		}
+	}
+	for id, e := range p.estimates {
+		if now.Sub(e.measuredAt) > estimateTTL {
+			delete(p.estimates, id)
+		}
+	}
+	p.lastSweep = now
+}
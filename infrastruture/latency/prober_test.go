@@ -0,0 +1,85 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestProber() (*Prober, *time.Time) {
+	p := NewProber().(*Prober)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	return p, &now
+}
+
+func TestProber(t *testing.T) {
+	player := uuid.New()
+
+	t.Run("Measure time between start and finish", func(t *testing.T) {
+		p, now := newTestProber()
+		nonce, err := p.Start(player)
+		assert.NoError(t, err)
+
+		*now = now.Add(80 * time.Millisecond)
+		rtt, err := p.Finish(player, nonce)
+		assert.NoError(t, err)
+		assert.Equal(t, 80*time.Millisecond, rtt)
+
+		latency, ok := p.Latency(player)
+		assert.True(t, ok)
+		assert.Equal(t, 80*time.Millisecond, latency)
+	})
+
+	t.Run("Smooth repeated samples", func(t *testing.T) {
+		p, now := newTestProber()
+		for _, sample := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+			nonce, _ := p.Start(player)
+			*now = now.Add(sample)
+			_, err := p.Finish(player, nonce)
+			assert.NoError(t, err)
+		}
+
+		latency, _ := p.Latency(player)
+		assert.Equal(t, 130*time.Millisecond, latency)
+	})
+
+	t.Run("Reject unknown, reused and other players' nonces", func(t *testing.T) {
+		p, _ := newTestProber()
+		nonce, _ := p.Start(player)
+
+		_, err := p.Finish(uuid.New(), nonce)
+		assert.Error(t, err)
+		_, err = p.Finish(player, "bogus")
+		assert.Error(t, err)
+
+		_, err = p.Finish(player, nonce)
+		assert.NoError(t, err)
+		_, err = p.Finish(player, nonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("Reject expired probes", func(t *testing.T) {
+		p, now := newTestProber()
+		nonce, _ := p.Start(player)
+
+		*now = now.Add(probeTTL + time.Second)
+		_, err := p.Finish(player, nonce)
+		assert.Error(t, err)
+
+		_, ok := p.Latency(player)
+		assert.False(t, ok)
+	})
+
+	t.Run("Forget stale estimates", func(t *testing.T) {
+		p, now := newTestProber()
+		nonce, _ := p.Start(player)
+		_, _ = p.Finish(player, nonce)
+
+		*now = now.Add(estimateTTL + time.Second)
+		_, ok := p.Latency(player)
+		assert.False(t, ok)
+	})
+}
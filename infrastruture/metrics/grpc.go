@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor records the latency and outcome of every unary gRPC call.
+func UnaryClientInterceptor(reg *Registry) grpc.UnaryClientInterceptor {
+	calls := reg.NewCounter("grpc_client_calls_total", "Total gRPC calls by method and status code.", "method", "code")
+	latency := reg.NewHistogram("grpc_client_call_duration_seconds", "gRPC call latency by method.", DefaultBuckets, "method")
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		latency.Observe(time.Since(start).Seconds(), method)
+		calls.Inc(method, status.Code(err).String())
+		return err
+	}
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CommandMonitor records the latency and failures of every Mongo command.
+func CommandMonitor(reg *Registry) *event.CommandMonitor {
+	latency := reg.NewHistogram("mongo_command_duration_seconds", "Mongo command latency by command name.", DefaultBuckets, "command")
+	failures := reg.NewCounter("mongo_command_failures_total", "Total failed Mongo commands by command name.", "command")
+
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			latency.Observe(e.Duration.Seconds(), e.CommandName)
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			latency.Observe(e.Duration.Seconds(), e.CommandName)
+			failures.Inc(e.CommandName)
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Render counter with labels", func(t *testing.T) {
+		reg := NewRegistry()
+		c := reg.NewCounter("requests_total", "Total requests.", "method", "status")
+		c.Inc("GET", "200")
+		c.Inc("GET", "200")
+		c.Inc("POST", "400")
+
+		var sb strings.Builder
+		assert.NoError(t, reg.Render(&sb))
+		assert.Equal(t, `# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total{method="GET",status="200"} 2
+requests_total{method="POST",status="400"} 1
+`, sb.String())
+	})
+
+	t.Run("Render gauge without labels", func(t *testing.T) {
+		reg := NewRegistry()
+		g := reg.NewGauge("in_flight", "In-flight requests.")
+		g.Add(3)
+		g.Add(-1)
+
+		var sb strings.Builder
+		assert.NoError(t, reg.Render(&sb))
+		assert.Contains(t, sb.String(), "in_flight 2\n")
+	})
+
+	t.Run("Render cumulative histogram", func(t *testing.T) {
+		reg := NewRegistry()
+		h := reg.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1}, "op")
+		h.Observe(0.05, "find")
+		h.Observe(0.5, "find")
+		h.Observe(2, "find")
+
+		var sb strings.Builder
+		assert.NoError(t, reg.Render(&sb))
+		out := sb.String()
+		assert.Contains(t, out, `latency_seconds_bucket{op="find",le="0.1"} 1`)
+		assert.Contains(t, out, `latency_seconds_bucket{op="find",le="1"} 2`)
+		assert.Contains(t, out, `latency_seconds_bucket{op="find",le="+Inf"} 3`)
+		assert.Contains(t, out, `latency_seconds_sum{op="find"} 2.55`)
+		assert.Contains(t, out, `latency_seconds_count{op="find"} 3`)
+	})
+
+	t.Run("Panic on label mismatch", func(t *testing.T) {
+		reg := NewRegistry()
+		c := reg.NewCounter("x_total", "X.", "a")
+		assert.Panics(t, func() { c.Inc() })
+	})
+}
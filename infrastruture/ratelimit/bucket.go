@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+)
+
+// idleTTL is how long an untouched bucket is kept before being evicted.
+const idleTTL = 10 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// TokenBucket is an in-memory, per-key token bucket rate limiter.
+// Implements i.AdjustableRateLimiter.
+type TokenBucket struct {
+	rate      float64 // Tokens added per second
+	burst     float64 // Maximum tokens a bucket can hold
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewTokenBucket creates a limiter that allows rate requests per second per key
+// with bursts of up to burst requests.
+func NewTokenBucket(rate float64, burst int) i.AdjustableRateLimiter {
+	return &TokenBucket{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow implements i.RateLimiter.
+func (tb *TokenBucket) Allow(key string) bool {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := tb.now()
+	tb.sweep(now)
+
+	b, ok := tb.buckets[key]
+	if !ok {
+		b = &bucket{tokens: tb.burst, lastSeen: now}
+		tb.buckets[key] = b
+	}
+
+	b.tokens = min(tb.burst, b.tokens+now.Sub(b.lastSeen).Seconds()*tb.rate)
+	b.lastSeen = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// SetLimits implements i.AdjustableRateLimiter.
+// Buckets holding more than the new burst are capped on their next use.
+func (tb *TokenBucket) SetLimits(rate float64, burst int) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.rate = rate
+	tb.burst = float64(burst)
+}
+
+// sweep evicts buckets that have been idle for longer than idleTTL.
+func (tb *TokenBucket) sweep(now time.Time) {
+	if now.Sub(tb.lastSweep) < idleTTL {
+		return
+	}
+
+	for key, b := range tb.buckets {
+		if now.Sub(b.lastSeen) > idleTTL {
+			delete(tb.buckets, key)
+		}
+	}
+	tb.lastSweep = now
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	t.Run("Allow up to burst then reject", func(t *testing.T) {
+		tb := NewTokenBucket(1, 3).(*TokenBucket)
+		now := time.Now()
+		tb.now = func() time.Time { return now }
+
+		for range 3 {
+			assert.True(t, tb.Allow("client"))
+		}
+		assert.False(t, tb.Allow("client"))
+	})
+
+	t.Run("Refill over time", func(t *testing.T) {
+		tb := NewTokenBucket(2, 1).(*TokenBucket)
+		now := time.Now()
+		tb.now = func() time.Time { return now }
+
+		assert.True(t, tb.Allow("client"))
+		assert.False(t, tb.Allow("client"))
+
+		now = now.Add(500 * time.Millisecond)
+		assert.True(t, tb.Allow("client"))
+	})
+
+	t.Run("Keys are limited independently", func(t *testing.T) {
+		tb := NewTokenBucket(1, 1).(*TokenBucket)
+		now := time.Now()
+		tb.now = func() time.Time { return now }
+
+		assert.True(t, tb.Allow("a"))
+		assert.False(t, tb.Allow("a"))
+		assert.True(t, tb.Allow("b"))
+	})
+
+	t.Run("Evict idle buckets", func(t *testing.T) {
+		tb := NewTokenBucket(1, 1).(*TokenBucket)
+		now := time.Now()
+		tb.now = func() time.Time { return now }
+
+		tb.Allow("idle")
+		now = now.Add(2 * idleTTL)
+		tb.Allow("active")
+
+		assert.NotContains(t, tb.buckets, "idle")
+		assert.Contains(t, tb.buckets, "active")
+	})
+
+	t.Run("Apply new limits to existing buckets", func(t *testing.T) {
+		tb := NewTokenBucket(1, 5).(*TokenBucket)
+		now := time.Now()
+		tb.now = func() time.Time { return now }
+
+		assert.True(t, tb.Allow("client"))
+		tb.SetLimits(1, 1)
+		assert.True(t, tb.Allow("client"))
+		assert.False(t, tb.Allow("client"))
+	})
+}
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventRepo handles the persistence of limited-time events.
+type EventRepo struct {
+	collection *mongo.Collection
+}
+
+// NewEventRepo creates a new EventRepo with the given MongoDB client, database name, and collection name.
+func NewEventRepo(client *mongo.Client, dbName, collectionName string) *EventRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &EventRepo{
+		collection: collection,
+	}
+}
+
+// Save inserts or replaces an event in the repository.
+func (e *EventRepo) Save(ctx context.Context, event *dmn.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": event.ID}
+	opts := options.Replace().SetUpsert(true)
+	_, err := e.collection.ReplaceOne(ctx, filter, event, opts)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+
+	return nil
+}
+
+// EndingAfter lists the events that have not ended by the given time, ordered by start time.
+// This includes both running and upcoming events.
+func (e *EventRepo) EndingAfter(ctx context.Context, t time.Time) ([]*dmn.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{"endsAt": bson.M{"$gt": t}}
+	opts := options.Find().SetSort(bson.M{"startsAt": 1})
+
+	cursor, err := e.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	events := make([]*dmn.Event, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return events, nil
+}
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FriendshipRepo handles the persistence of friendships and friend requests.
+type FriendshipRepo struct {
+	collection *mongo.Collection
+}
+
+// NewFriendshipRepo creates a new FriendshipRepo with the given MongoDB client, database name, and collection name.
+func NewFriendshipRepo(client *mongo.Client, dbName, collectionName string) *FriendshipRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &FriendshipRepo{
+		collection: collection,
+	}
+}
+
+// EnsureIndexes creates the indexes lookups by requester and addressee use.
+// The requester index also serves lookups of the pair.
+func (f *FriendshipRepo) EnsureIndexes(ctx context.Context) error {
+	_, err := f.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "requesterID", Value: 1}, {Key: "addresseeID", Value: 1}}},
+		{Keys: bson.D{{Key: "addresseeID", Value: 1}}},
+	})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// Insert adds a new friendship to the repository.
+// Returns an error if the friendship already exists or if an unexpected error occurs.
+func (f *FriendshipRepo) Insert(ctx context.Context, friendship *dmn.Friendship) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	if _, err := f.collection.InsertOne(ctx, friendship); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("friend request already exists")
+		}
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// Accept stores the acceptance of a friendship that is still pending.
+// The update only matches a pending friendship, so a request removed in the meantime stays removed.
+// Returns an error if no pending friendship has its ID or if an unexpected error occurs.
+func (f *FriendshipRepo) Accept(ctx context.Context, friendship *dmn.Friendship) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": friendship.ID, "status": dmn.FriendshipPending}
+	update := bson.M{"$set": bson.M{"status": friendship.Status, "acceptedAt": friendship.AcceptedAt}}
+	result, err := f.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("friendship not found")
+	}
+	return nil
+}
+
+// Between retrieves the friendship between two users, whoever requested it.
+// Returns an error if there is none or if an unexpected error occurs.
+func (f *FriendshipRepo) Between(ctx context.Context, userID, otherID uuid.UUID) (*dmn.Friendship, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"requesterID": userID, "addresseeID": otherID},
+			bson.M{"requesterID": otherID, "addresseeID": userID},
+		},
+	}
+	var friendship dmn.Friendship
+	if err := f.collection.FindOne(ctx, filter).Decode(&friendship); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("friendship not found")
+		}
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return &friendship, nil
+}
+
+// ByUser lists the friendships and friend requests of a user, oldest first.
+func (f *FriendshipRepo) ByUser(ctx context.Context, userID uuid.UUID) ([]*dmn.Friendship, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"requesterID": userID},
+			bson.M{"addresseeID": userID},
+		},
+	}
+	opts := options.Find().SetSort(bson.M{"createdAt": 1})
+
+	cursor, err := f.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	friendships := make([]*dmn.Friendship, 0)
+	if err := cursor.All(ctx, &friendships); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return friendships, nil
+}
+
+// Delete removes a friendship from the repository.
+// Returns an error if the friendship is not found or if an unexpected error occurs.
+func (f *FriendshipRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	result, err := f.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("friendship not found")
+	}
+	return nil
+}
+
+// DeleteByUser removes every friendship and friend request of a user.
+func (f *FriendshipRepo) DeleteByUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"requesterID": userID},
+			bson.M{"addresseeID": userID},
+		},
+	}
+	if _, err := f.collection.DeleteMany(ctx, filter); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
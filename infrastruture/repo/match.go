@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MatchRepo handles the persistence of match results.
+type MatchRepo struct {
+	collection *mongo.Collection
+	reads      *mongo.Collection // History queries; may be served by secondaries
+}
+
+// NewMatchRepo creates a new MatchRepo with the given MongoDB client, database name, and collection name.
+// History queries use the heavyReads read preference; nil keeps them on the primary.
+func NewMatchRepo(client *mongo.Client, dbName, collectionName string, heavyReads *readpref.ReadPref) *MatchRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &MatchRepo{
+		collection: collection,
+		reads:      readCollection(collection, heavyReads),
+	}
+}
+
+// EnsureIndexes creates the indexes history queries and the daily game count use.
+func (m *MatchRepo) EnsureIndexes(ctx context.Context) error {
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "playerIDs", Value: 1}, {Key: "endedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "endedAt", Value: -1}}},
+	})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// Insert adds a match result that has not been recorded yet.
+// Returns an error if a result with the same ID exists or if an unexpected error occurs.
+func (m *MatchRepo) Insert(ctx context.Context, match *dmn.MatchResult) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if _, err := m.collection.InsertOne(ctx, match); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return dmn.ErrMatchRecorded
+		}
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// Save inserts or replaces a match result in the repository.
+func (m *MatchRepo) Save(ctx context.Context, match *dmn.MatchResult) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": match.ID}
+	opts := options.Replace().SetUpsert(true)
+	_, err := m.collection.ReplaceOne(ctx, filter, match, opts)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+
+	return nil
+}
+
+// ByID retrieves a match result by its ID.
+// Returns an error if the match is not found or if an unexpected error occurs.
+func (m *MatchRepo) ByID(ctx context.Context, id uuid.UUID) (*dmn.MatchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var match dmn.MatchResult
+	if err := m.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&match); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("match not found")
+		}
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return &match, nil
+}
+
+// ByPlayer lists the matches a player took part in, most recently ended first.
+func (m *MatchRepo) ByPlayer(ctx context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error) {
+	return m.find(ctx, bson.M{"playerIDs": playerID}, offset, limit)
+}
+
+// Between lists the matches both players took part in, most recently ended first.
+func (m *MatchRepo) Between(ctx context.Context, playerID, opponentID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error) {
+	return m.find(ctx, bson.M{"playerIDs": bson.M{"$all": bson.A{playerID, opponentID}}}, offset, limit)
+}
+
+// CountEndedSince counts the matches that ended at or after the given time.
+func (m *MatchRepo) CountEndedSince(ctx context.Context, since time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	count, err := m.reads.CountDocuments(ctx, bson.M{"endedAt": bson.M{"$gte": since}})
+	if err != nil {
+		return 0, errors.New("unexpected error: " + err.Error())
+	}
+	return count, nil
+}
+
+// AnonymizePlayer replaces the player's ID with anonymousID in the player IDs
+// and the player results of every match the player took part in.
+func (m *MatchRepo) AnonymizePlayer(ctx context.Context, playerID, anonymousID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{
+		"playerIDs.$[player]":        anonymousID,
+		"players.$[result].playerID": anonymousID,
+	}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"player": playerID},
+		bson.M{"result.playerID": playerID},
+	}})
+	if _, err := m.collection.UpdateMany(ctx, bson.M{"playerIDs": playerID}, update, opts); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// find lists the matching results, most recently ended first; a zero limit lists all.
+func (m *MatchRepo) find(ctx context.Context, filter bson.M, offset, limit int) ([]*dmn.MatchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "endedAt", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := m.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	matches := make([]*dmn.MatchResult, 0, limit)
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return matches, nil
+}
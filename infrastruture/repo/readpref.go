@@ -0,0 +1,18 @@
+package repo
+
+import (
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// readCollection returns a handle on the collection that routes reads by rp.
+// Heavy, staleness-tolerant queries use it so they can be served by secondaries,
+// while writes and read-your-writes lookups keep using the primary.
+// A nil rp returns the collection unchanged.
+func readCollection(collection *mongo.Collection, rp *readpref.ReadPref) *mongo.Collection {
+	if rp == nil {
+		return collection
+	}
+	return collection.Database().Collection(collection.Name(), options.Collection().SetReadPreference(rp))
+}
@@ -0,0 +1,215 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ReplayRepo handles the persistence of match replays.
+// Frames are stored one per document in a separate collection, so long games
+// stay below MongoDB's 16MB document limit.
+type ReplayRepo struct {
+	collection *mongo.Collection
+	frames     *mongo.Collection
+	reads      *mongo.Collection // Match history listings; may be served by secondaries
+}
+
+// frameDocument is a replay frame stored in the frames collection.
+type frameDocument struct {
+	ReplayID        uuid.UUID `bson:"replayID"`
+	dmn.ReplayFrame `bson:",inline"`
+}
+
+// NewReplayRepo creates a new ReplayRepo with the given MongoDB client, database name, and the
+// names of the replay and frame collections.
+// Match history listings use the heavyReads read preference; nil keeps them on the primary.
+func NewReplayRepo(client *mongo.Client, dbName, collectionName, framesCollectionName string, heavyReads *readpref.ReadPref) *ReplayRepo {
+	db := client.Database(dbName)
+	collection := db.Collection(collectionName)
+	return &ReplayRepo{
+		collection: collection,
+		frames:     db.Collection(framesCollectionName),
+		reads:      readCollection(collection, heavyReads),
+	}
+}
+
+// EnsureIndexes creates the indexes frames are streamed by and replays are listed by.
+func (r *ReplayRepo) EnsureIndexes(ctx context.Context) error {
+	_, err := r.frames.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "replayID", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+
+	_, err = r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "playerIDs", Value: 1}, {Key: "startedAt", Value: -1}},
+	})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// Save inserts or replaces a replay and its frames in the repository.
+func (r *ReplayRepo) Save(ctx context.Context, replay *dmn.Replay) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	meta := *replay
+	meta.Frames = nil
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": replay.ID}, &meta, opts); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+
+	if _, err := r.frames.DeleteMany(ctx, bson.M{"replayID": replay.ID}); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	if len(replay.Frames) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(replay.Frames))
+	for _, frame := range replay.Frames {
+		docs = append(docs, frameDocument{ReplayID: replay.ID, ReplayFrame: frame})
+	}
+	if _, err := r.frames.InsertMany(ctx, docs); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// ByID retrieves a replay, without its frames, by its ID.
+// Returns an error if the replay is not found or if an unexpected error occurs.
+func (r *ReplayRepo) ByID(ctx context.Context, id uuid.UUID) (*dmn.Replay, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id}
+	opts := options.FindOne().SetProjection(bson.M{"frames": 0})
+	var replay dmn.Replay
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&replay); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, dmn.ErrReplayNotFound
+		}
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return &replay, nil
+}
+
+// EachFrame calls fn with every frame of the replay in version order, reading them
+// from a cursor, and stops at the first error fn returns. It is bounded by ctx alone,
+// since streaming a long game takes as long as the client reads.
+// Replays recorded before frames moved to their own collection are read from the
+// frames embedded in the replay.
+func (r *ReplayRepo) EachFrame(ctx context.Context, id uuid.UUID, fn func(*dmn.ReplayFrame) error) error {
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 0, "replayID": 0}).
+		SetSort(bson.D{{Key: "version", Value: 1}})
+
+	cursor, err := r.frames.Find(ctx, bson.M{"replayID": id}, opts)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	defer cursor.Close(ctx)
+
+	found := false
+	for cursor.Next(ctx) {
+		found = true
+		var frame dmn.ReplayFrame
+		if err := cursor.Decode(&frame); err != nil {
+			return errors.New("unexpected error: " + err.Error())
+		}
+		if err := fn(&frame); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	if found {
+		return nil
+	}
+
+	var legacy dmn.Replay
+	findOpts := options.FindOne().SetProjection(bson.M{"frames": 1})
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}, findOpts).Decode(&legacy); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return dmn.ErrReplayNotFound
+		}
+		return errors.New("unexpected error: " + err.Error())
+	}
+	for idx := range legacy.Frames {
+		if err := fn(&legacy.Frames[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnonymizePlayer replaces the player's ID with anonymousID in the player list
+// and the frames of every replay the player took part in.
+func (r *ReplayRepo) AnonymizePlayer(ctx context.Context, playerID, anonymousID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Embedded frames of older replays are updated separately since replays without
+	// frames have no frames array, which a filtered positional update would reject.
+	updates := []struct {
+		filter bson.M
+		field  string
+		match  bson.M
+	}{
+		{filter: bson.M{"playerIDs": playerID}, field: "playerIDs.$[player]", match: bson.M{"player": playerID}},
+		{filter: bson.M{"frames.playerID": playerID}, field: "frames.$[frame].playerID", match: bson.M{"frame.playerID": playerID}},
+	}
+
+	for _, u := range updates {
+		update := bson.M{"$set": bson.M{u.field: anonymousID}}
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{u.match}})
+		if _, err := r.collection.UpdateMany(ctx, u.filter, update, opts); err != nil {
+			return errors.New("unexpected error: " + err.Error())
+		}
+	}
+
+	update := bson.M{"$set": bson.M{"playerID": anonymousID}}
+	if _, err := r.frames.UpdateMany(ctx, bson.M{"playerID": playerID}, update); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// ByPlayer lists a page of the replays a player took part in, newest first.
+// Frames are not loaded; use EachFrame to stream them.
+func (r *ReplayRepo) ByPlayer(ctx context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.Replay, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{"playerIDs": playerID}
+	opts := options.Find().
+		SetProjection(bson.M{"frames": 0}).
+		SetSort(bson.M{"startedAt": -1}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.reads.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	replays := make([]*dmn.Replay, 0)
+	if err := cursor.All(ctx, &replays); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return replays, nil
+}
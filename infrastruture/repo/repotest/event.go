@@ -0,0 +1,48 @@
+package repotest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryEventRepo is an in-memory i.EventRepo.
+type InMemoryEventRepo struct {
+	events map[uuid.UUID]dmn.Event
+	mu     sync.RWMutex
+}
+
+// NewInMemoryEventRepo creates an empty InMemoryEventRepo.
+func NewInMemoryEventRepo() *InMemoryEventRepo {
+	return &InMemoryEventRepo{events: make(map[uuid.UUID]dmn.Event)}
+}
+
+// Save implements i.EventRepo.
+func (e *InMemoryEventRepo) Save(_ context.Context, event *dmn.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.events[event.ID] = *event
+	return nil
+}
+
+// EndingAfter implements i.EventRepo. Events are listed by start time.
+func (e *InMemoryEventRepo) EndingAfter(_ context.Context, t time.Time) ([]*dmn.Event, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	events := make([]*dmn.Event, 0)
+	for _, event := range e.events {
+		if event.EndsAt.After(t) {
+			events = append(events, &event)
+		}
+	}
+	sort.Slice(events, func(a, b int) bool {
+		return events[a].StartsAt.Before(events[b].StartsAt)
+	})
+	return events, nil
+}
@@ -0,0 +1,105 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryFriendshipRepo is an in-memory i.FriendshipRepo.
+type InMemoryFriendshipRepo struct {
+	friendships map[uuid.UUID]dmn.Friendship
+	mu          sync.RWMutex
+}
+
+// NewInMemoryFriendshipRepo creates an empty InMemoryFriendshipRepo.
+func NewInMemoryFriendshipRepo() *InMemoryFriendshipRepo {
+	return &InMemoryFriendshipRepo{friendships: make(map[uuid.UUID]dmn.Friendship)}
+}
+
+// Insert implements i.FriendshipRepo.
+func (f *InMemoryFriendshipRepo) Insert(_ context.Context, friendship *dmn.Friendship) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.friendships[friendship.ID]; ok {
+		return errors.New("friend request already exists")
+	}
+	f.friendships[friendship.ID] = *friendship
+	return nil
+}
+
+// Accept implements i.FriendshipRepo.
+func (f *InMemoryFriendshipRepo) Accept(_ context.Context, friendship *dmn.Friendship) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.friendships[friendship.ID]
+	if !ok || stored.Status != dmn.FriendshipPending {
+		return errors.New("friendship not found")
+	}
+	stored.Status = friendship.Status
+	stored.AcceptedAt = friendship.AcceptedAt
+	f.friendships[friendship.ID] = stored
+	return nil
+}
+
+// Between implements i.FriendshipRepo.
+func (f *InMemoryFriendshipRepo) Between(_ context.Context, userID, otherID uuid.UUID) (*dmn.Friendship, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, friendship := range f.friendships {
+		if (friendship.RequesterID == userID && friendship.AddresseeID == otherID) ||
+			(friendship.RequesterID == otherID && friendship.AddresseeID == userID) {
+			return &friendship, nil
+		}
+	}
+	return nil, errors.New("friendship not found")
+}
+
+// ByUser implements i.FriendshipRepo. Friendships are listed oldest first.
+func (f *InMemoryFriendshipRepo) ByUser(_ context.Context, userID uuid.UUID) ([]*dmn.Friendship, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	friendships := make([]*dmn.Friendship, 0)
+	for _, friendship := range f.friendships {
+		if friendship.RequesterID == userID || friendship.AddresseeID == userID {
+			friendships = append(friendships, &friendship)
+		}
+	}
+	sort.Slice(friendships, func(a, b int) bool {
+		return friendships[a].CreatedAt.Before(friendships[b].CreatedAt)
+	})
+	return friendships, nil
+}
+
+// Delete implements i.FriendshipRepo.
+func (f *InMemoryFriendshipRepo) Delete(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.friendships[id]; !ok {
+		return errors.New("friendship not found")
+	}
+	delete(f.friendships, id)
+	return nil
+}
+
+// DeleteByUser implements i.FriendshipRepo.
+func (f *InMemoryFriendshipRepo) DeleteByUser(_ context.Context, userID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for id, friendship := range f.friendships {
+		if friendship.RequesterID == userID || friendship.AddresseeID == userID {
+			delete(f.friendships, id)
+		}
+	}
+	return nil
+}
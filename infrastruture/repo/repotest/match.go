@@ -0,0 +1,146 @@
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryMatchRepo is an in-memory i.MatchRepo.
+type InMemoryMatchRepo struct {
+	matches map[uuid.UUID]dmn.MatchResult
+	mu      sync.RWMutex
+}
+
+// NewInMemoryMatchRepo creates an empty InMemoryMatchRepo.
+func NewInMemoryMatchRepo() *InMemoryMatchRepo {
+	return &InMemoryMatchRepo{matches: make(map[uuid.UUID]dmn.MatchResult)}
+}
+
+// Insert implements i.MatchRepo.
+func (m *InMemoryMatchRepo) Insert(_ context.Context, match *dmn.MatchResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.matches[match.ID]; ok {
+		return dmn.ErrMatchRecorded
+	}
+	m.store(match)
+	return nil
+}
+
+// Save implements i.MatchRepo.
+func (m *InMemoryMatchRepo) Save(_ context.Context, match *dmn.MatchResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(match)
+	return nil
+}
+
+// store keeps a copy of the match; callers hold the write lock.
+func (m *InMemoryMatchRepo) store(match *dmn.MatchResult) {
+	stored := *match
+	stored.PlayerIDs = slices.Clone(match.PlayerIDs)
+	stored.Players = slices.Clone(match.Players)
+	m.matches[match.ID] = stored
+}
+
+// ByID implements i.MatchRepo.
+func (m *InMemoryMatchRepo) ByID(_ context.Context, id uuid.UUID) (*dmn.MatchResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	match, ok := m.matches[id]
+	if !ok {
+		return nil, errors.New("match not found")
+	}
+	match.PlayerIDs = slices.Clone(match.PlayerIDs)
+	match.Players = slices.Clone(match.Players)
+	return &match, nil
+}
+
+// ByPlayer implements i.MatchRepo.
+func (m *InMemoryMatchRepo) ByPlayer(_ context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error) {
+	return m.find([]uuid.UUID{playerID}, offset, limit), nil
+}
+
+// Between implements i.MatchRepo.
+func (m *InMemoryMatchRepo) Between(_ context.Context, playerID, opponentID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error) {
+	return m.find([]uuid.UUID{playerID, opponentID}, offset, limit), nil
+}
+
+// CountEndedSince implements i.MatchRepo.
+func (m *InMemoryMatchRepo) CountEndedSince(_ context.Context, since time.Time) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, match := range m.matches {
+		if !match.EndedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// AnonymizePlayer implements i.MatchRepo.
+func (m *InMemoryMatchRepo) AnonymizePlayer(_ context.Context, playerID, anonymousID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, match := range m.matches {
+		if !slices.Contains(match.PlayerIDs, playerID) {
+			continue
+		}
+		// Copies handed out by the lookups share the slices; replace them instead.
+		match.PlayerIDs = slices.Clone(match.PlayerIDs)
+		match.Players = slices.Clone(match.Players)
+		for i, p := range match.PlayerIDs {
+			if p == playerID {
+				match.PlayerIDs[i] = anonymousID
+			}
+		}
+		for i, p := range match.Players {
+			if p.PlayerID == playerID {
+				match.Players[i].PlayerID = anonymousID
+			}
+		}
+		m.matches[id] = match
+	}
+	return nil
+}
+
+// find lists the matches all the players took part in, most recently ended first;
+// a zero limit lists all.
+func (m *InMemoryMatchRepo) find(playerIDs []uuid.UUID, offset, limit int) []*dmn.MatchResult {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	matches := make([]*dmn.MatchResult, 0)
+	for _, match := range m.matches {
+		all := true
+		for _, id := range playerIDs {
+			all = all && slices.Contains(match.PlayerIDs, id)
+		}
+		if all {
+			match.PlayerIDs = slices.Clone(match.PlayerIDs)
+			match.Players = slices.Clone(match.Players)
+			matches = append(matches, &match)
+		}
+	}
+	sort.Slice(matches, func(a, b int) bool {
+		if !matches[a].EndedAt.Equal(matches[b].EndedAt) {
+			return matches[a].EndedAt.After(matches[b].EndedAt)
+		}
+		return bytes.Compare(matches[a].ID[:], matches[b].ID[:]) < 0
+	})
+	return window(matches, offset, limit)
+}
@@ -0,0 +1,109 @@
+package repotest
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryReplayRepo is an in-memory i.ReplayRepo.
+type InMemoryReplayRepo struct {
+	replays map[uuid.UUID]dmn.Replay
+	mu      sync.RWMutex
+}
+
+// NewInMemoryReplayRepo creates an empty InMemoryReplayRepo.
+func NewInMemoryReplayRepo() *InMemoryReplayRepo {
+	return &InMemoryReplayRepo{replays: make(map[uuid.UUID]dmn.Replay)}
+}
+
+// Save implements i.ReplayRepo.
+func (r *InMemoryReplayRepo) Save(_ context.Context, replay *dmn.Replay) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *replay
+	stored.PlayerIDs = slices.Clone(replay.PlayerIDs)
+	stored.Frames = slices.Clone(replay.Frames)
+	r.replays[replay.ID] = stored
+	return nil
+}
+
+// ByID implements i.ReplayRepo.
+func (r *InMemoryReplayRepo) ByID(_ context.Context, id uuid.UUID) (*dmn.Replay, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	replay, ok := r.replays[id]
+	if !ok {
+		return nil, dmn.ErrReplayNotFound
+	}
+	replay.PlayerIDs = slices.Clone(replay.PlayerIDs)
+	replay.Frames = nil
+	return &replay, nil
+}
+
+// EachFrame implements i.ReplayRepo.
+func (r *InMemoryReplayRepo) EachFrame(_ context.Context, id uuid.UUID, fn func(*dmn.ReplayFrame) error) error {
+	r.mu.RLock()
+	replay, ok := r.replays[id]
+	frames := slices.Clone(replay.Frames)
+	r.mu.RUnlock()
+
+	if !ok {
+		return dmn.ErrReplayNotFound
+	}
+	sort.SliceStable(frames, func(a, b int) bool {
+		return frames[a].Version < frames[b].Version
+	})
+	for idx := range frames {
+		if err := fn(&frames[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ByPlayer implements i.ReplayRepo. Replays are listed newest first, without frames.
+func (r *InMemoryReplayRepo) ByPlayer(_ context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.Replay, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	replays := make([]*dmn.Replay, 0)
+	for _, replay := range r.replays {
+		if slices.Contains(replay.PlayerIDs, playerID) {
+			replay.PlayerIDs = slices.Clone(replay.PlayerIDs)
+			replay.Frames = nil
+			replays = append(replays, &replay)
+		}
+	}
+	sort.Slice(replays, func(a, b int) bool {
+		return replays[a].StartedAt.After(replays[b].StartedAt)
+	})
+	return window(replays, offset, limit), nil
+}
+
+// AnonymizePlayer implements i.ReplayRepo.
+func (r *InMemoryReplayRepo) AnonymizePlayer(_ context.Context, playerID, anonymousID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, replay := range r.replays {
+		for i, p := range replay.PlayerIDs {
+			if p == playerID {
+				replay.PlayerIDs[i] = anonymousID
+			}
+		}
+		for i, f := range replay.Frames {
+			if f.PlayerID == playerID {
+				replay.Frames[i].PlayerID = anonymousID
+			}
+		}
+		r.replays[id] = replay
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryReplayRepo(t *testing.T) {
+	t.Run("List a player's replays newest first", func(t *testing.T) {
+		repo := NewInMemoryReplayRepo()
+		playerID := uuid.New()
+		start := time.Now()
+		for _, offset := range []time.Duration{2, 0, 3, 1} {
+			replay := &dmn.Replay{ID: uuid.New(), PlayerIDs: []uuid.UUID{playerID}, StartedAt: start.Add(offset * time.Minute)}
+			assert.NoError(t, repo.Save(context.Background(), replay))
+		}
+
+		replays, err := repo.ByPlayer(context.Background(), playerID, 0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, replays, 4)
+		for idx := 1; idx < len(replays); idx++ {
+			assert.True(t, replays[idx-1].StartedAt.After(replays[idx].StartedAt))
+		}
+
+		page, err := repo.ByPlayer(context.Background(), playerID, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, replays[1:3], page)
+	})
+	t.Run("Stream frames in version order", func(t *testing.T) {
+		repo := NewInMemoryReplayRepo()
+		replay := &dmn.Replay{ID: uuid.New(), Frames: []dmn.ReplayFrame{{Version: 3}, {Version: 1}, {Version: 2}}}
+		assert.NoError(t, repo.Save(context.Background(), replay))
+
+		stored, err := repo.ByID(context.Background(), replay.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, stored.Frames)
+
+		versions := make([]int64, 0, 3)
+		err = repo.EachFrame(context.Background(), replay.ID, func(frame *dmn.ReplayFrame) error {
+			versions = append(versions, frame.Version)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3}, versions)
+
+		err = repo.EachFrame(context.Background(), uuid.New(), func(*dmn.ReplayFrame) error { return nil })
+		assert.ErrorIs(t, err, dmn.ErrReplayNotFound)
+	})
+}
@@ -0,0 +1,62 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+)
+
+// InMemorySeasonRepo is an in-memory i.SeasonRepo.
+type InMemorySeasonRepo struct {
+	seasons map[int]dmn.Season
+	mu      sync.RWMutex
+}
+
+// NewInMemorySeasonRepo creates an empty InMemorySeasonRepo.
+func NewInMemorySeasonRepo() *InMemorySeasonRepo {
+	return &InMemorySeasonRepo{seasons: make(map[int]dmn.Season)}
+}
+
+// Save implements i.SeasonRepo.
+func (s *InMemorySeasonRepo) Save(_ context.Context, season *dmn.Season) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *season
+	stored.Standings = slices.Clone(season.Standings)
+	s.seasons[season.Number] = stored
+	return nil
+}
+
+// Latest implements i.SeasonRepo. Like the MongoDB repository, it omits the standings.
+func (s *InMemorySeasonRepo) Latest(_ context.Context) (*dmn.Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *dmn.Season
+	for _, season := range s.seasons {
+		if latest == nil || season.Number > latest.Number {
+			latest = &season
+		}
+	}
+	if latest != nil {
+		latest.Standings = nil
+	}
+	return latest, nil
+}
+
+// ByNumber implements i.SeasonRepo.
+func (s *InMemorySeasonRepo) ByNumber(_ context.Context, number int) (*dmn.Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	season, ok := s.seasons[number]
+	if !ok {
+		return nil, errors.New("season not found")
+	}
+	season.Standings = slices.Clone(season.Standings)
+	return &season, nil
+}
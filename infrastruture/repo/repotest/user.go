@@ -0,0 +1,224 @@
+// Package repotest provides in-memory implementations of the repository
+// interfaces in service/i, for tests and local development without MongoDB.
+// They mirror the ordering and error messages of the MongoDB repositories.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// InMemoryUserRepo is an in-memory i.UserRepo.
+type InMemoryUserRepo struct {
+	users map[uuid.UUID]dmn.User
+	mu    sync.RWMutex
+}
+
+// NewInMemoryUserRepo creates an empty InMemoryUserRepo.
+func NewInMemoryUserRepo() *InMemoryUserRepo {
+	return &InMemoryUserRepo{users: make(map[uuid.UUID]dmn.User)}
+}
+
+// Save implements i.UserRepo. Like the MongoDB repository, it only sets the
+// placement matches of new users and never changes the reset season or the
+// last placement match.
+func (u *InMemoryUserRepo) Save(_ context.Context, user *dmn.User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for id, other := range u.users {
+		if id != user.ID && other.Username == user.Username {
+			return errors.New("username conflict")
+		}
+	}
+
+	stored := *user
+	stored.Roles = append([]string(nil), user.Roles...)
+	if existing, ok := u.users[user.ID]; ok {
+		stored.Rating = existing.Rating
+		stored.PlacementMatchesLeft = existing.PlacementMatchesLeft
+	}
+	stored.ResetSeason = u.users[user.ID].ResetSeason
+	stored.LastPlacementMatch = u.users[user.ID].LastPlacementMatch
+	u.users[user.ID] = stored
+	return nil
+}
+
+// ByID implements i.UserRepo.
+func (u *InMemoryUserRepo) ByID(_ context.Context, id uuid.UUID) (*dmn.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	user, ok := u.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return &user, nil
+}
+
+// ByIDs implements i.UserRepo.
+func (u *InMemoryUserRepo) ByIDs(_ context.Context, ids []uuid.UUID) ([]*dmn.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]*dmn.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := u.users[id]; ok {
+			users = append(users, &user)
+		}
+	}
+	return users, nil
+}
+
+// ByUsername implements i.UserRepo.
+func (u *InMemoryUserRepo) ByUsername(_ context.Context, username string) (*dmn.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, user := range u.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+// Delete implements i.UserRepo.
+func (u *InMemoryUserRepo) Delete(_ context.Context, id uuid.UUID) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[id]; !ok {
+		return errors.New("user not found")
+	}
+	delete(u.users, id)
+	return nil
+}
+
+// ByRating implements i.UserRepo.
+func (u *InMemoryUserRepo) ByRating(_ context.Context, offset, limit int) ([]*dmn.User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]*dmn.User, 0, len(u.users))
+	for _, user := range u.users {
+		users = append(users, &user)
+	}
+	sort.Slice(users, func(a, b int) bool {
+		return ahead(users[a], users[b].Rating, users[b].Username)
+	})
+	return window(users, offset, limit), nil
+}
+
+// CountAhead implements i.UserRepo.
+func (u *InMemoryUserRepo) CountAhead(_ context.Context, rating int, username string) (int64, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	var count int64
+	for _, user := range u.users {
+		if ahead(&user, rating, username) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SoftResetRatings implements i.UserRepo.
+func (u *InMemoryUserRepo) SoftResetRatings(_ context.Context, season int, reset dmn.SeasonReset) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for id, user := range u.users {
+		if user.ResetSeason == season {
+			continue
+		}
+		user.ResetSeason = season
+		user.Rating = int(math.Round(float64(reset.Mean) + float64(user.Rating-reset.Mean)*reset.Keep))
+		user.PlacementMatchesLeft = reset.PlacementMatches
+		u.users[id] = user
+	}
+	return nil
+}
+
+// UsePlacementMatch implements i.UserRepo.
+func (u *InMemoryUserRepo) UsePlacementMatch(_ context.Context, id, matchID uuid.UUID) (bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, ok := u.users[id]
+	if !ok {
+		return false, nil
+	}
+	if user.LastPlacementMatch == matchID {
+		return true, nil
+	}
+	if user.PlacementMatchesLeft <= 0 {
+		return false, nil
+	}
+	user.PlacementMatchesLeft--
+	user.LastPlacementMatch = matchID
+	u.users[id] = user
+	return true, nil
+}
+
+// List implements i.UserRepo.
+func (u *InMemoryUserRepo) List(_ context.Context, filter dmn.UserFilter, order dmn.UserSort, page, pageSize int) ([]*dmn.User, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("invalid page")
+	}
+	if !order.Valid() {
+		return nil, errors.New("invalid sort")
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]*dmn.User, 0)
+	for _, user := range u.users {
+		if (filter.MinRating != nil && user.Rating < *filter.MinRating) ||
+			(filter.MaxRating != nil && user.Rating > *filter.MaxRating) ||
+			!strings.HasPrefix(user.Username, filter.UsernamePrefix) {
+			continue
+		}
+		user.PasswordHash = ""
+		users = append(users, &user)
+	}
+
+	sort.Slice(users, func(a, b int) bool {
+		switch order {
+		case dmn.UserSortRatingDesc:
+			return ahead(users[a], users[b].Rating, users[b].Username)
+		case dmn.UserSortRatingAsc:
+			if users[a].Rating != users[b].Rating {
+				return users[a].Rating < users[b].Rating
+			}
+		}
+		return users[a].Username < users[b].Username
+	})
+	return window(users, (page-1)*pageSize, pageSize), nil
+}
+
+// ahead reports whether ByRating orders the user before the given rating and username.
+func ahead(user *dmn.User, rating int, username string) bool {
+	return user.Rating > rating || (user.Rating == rating && user.Username < username)
+}
+
+// window returns the items in [offset, offset+limit); a zero limit returns all items from offset.
+func window[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,92 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryUserRepo(t *testing.T) {
+	newUser := func(username string, rating int) *dmn.User {
+		return &dmn.User{ID: uuid.New(), Username: username, Rating: rating}
+	}
+
+	t.Run("Reject taken usernames", func(t *testing.T) {
+		repo := NewInMemoryUserRepo()
+		assert.NoError(t, repo.Save(context.Background(), newUser("abebe", 1500)))
+		assert.EqualError(t, repo.Save(context.Background(), newUser("abebe", 1500)), "username conflict")
+	})
+
+	t.Run("Order by rating then username", func(t *testing.T) {
+		repo := NewInMemoryUserRepo()
+		for _, u := range []*dmn.User{newUser("chala", 1400), newUser("bekele", 1500), newUser("abebe", 1500)} {
+			assert.NoError(t, repo.Save(context.Background(), u))
+		}
+
+		users, err := repo.ByRating(context.Background(), 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "bekele", users[0].Username)
+		assert.Equal(t, "chala", users[1].Username)
+
+		ahead, err := repo.CountAhead(context.Background(), 1400, "chala")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), ahead)
+	})
+
+	t.Run("Reset ratings and consume placements", func(t *testing.T) {
+		repo := NewInMemoryUserRepo()
+		user := newUser("abebe", 1800)
+		assert.NoError(t, repo.Save(context.Background(), user))
+		assert.NoError(t, repo.SoftResetRatings(context.Background(), 1, dmn.SeasonReset{Mean: 1400, Keep: 0.5, PlacementMatches: 1}))
+
+		// Saving a user never touches its placement matches.
+		stored, _ := repo.ByID(context.Background(), user.ID)
+		assert.Equal(t, 1600, stored.Rating)
+		assert.NoError(t, repo.Save(context.Background(), stored))
+
+		matchID := uuid.New()
+		used, err := repo.UsePlacementMatch(context.Background(), user.ID, matchID)
+		assert.NoError(t, err)
+		assert.True(t, used)
+		used, _ = repo.UsePlacementMatch(context.Background(), user.ID, matchID)
+		assert.True(t, used, "a retry for the same match reports the same")
+		used, _ = repo.UsePlacementMatch(context.Background(), user.ID, uuid.New())
+		assert.False(t, used)
+
+		// A second reset for the same season is skipped.
+		assert.NoError(t, repo.SoftResetRatings(context.Background(), 1, dmn.SeasonReset{Mean: 1400, Keep: 0.5, PlacementMatches: 1}))
+		stored, _ = repo.ByID(context.Background(), user.ID)
+		assert.Equal(t, 1600, stored.Rating)
+		assert.Equal(t, 0, stored.PlacementMatchesLeft)
+	})
+
+	t.Run("Keep a rating reset by a stale save", func(t *testing.T) {
+		repo := NewInMemoryUserRepo()
+		user := newUser("abebe", 1800)
+		assert.NoError(t, repo.Save(context.Background(), user))
+
+		stale, _ := repo.ByID(context.Background(), user.ID)
+		assert.NoError(t, repo.SoftResetRatings(context.Background(), 1, dmn.SeasonReset{Mean: 1400, Keep: 0.5}))
+		stale.Username = "abebe_k"
+		assert.NoError(t, repo.Save(context.Background(), stale))
+
+		stored, _ := repo.ByID(context.Background(), user.ID)
+		assert.Equal(t, "abebe_k", stored.Username)
+		assert.Equal(t, 1600, stored.Rating)
+	})
+
+	t.Run("Set placements of new users only", func(t *testing.T) {
+		repo := NewInMemoryUserRepo()
+		user := newUser("abebe", 1500)
+		user.PlacementMatchesLeft = 2
+		assert.NoError(t, repo.Save(context.Background(), user))
+
+		user.PlacementMatchesLeft = 5
+		assert.NoError(t, repo.Save(context.Background(), user))
+		stored, _ := repo.ByID(context.Background(), user.ID)
+		assert.Equal(t, 2, stored.PlacementMatchesLeft)
+	})
+}
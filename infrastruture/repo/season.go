@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SeasonRepo handles the persistence of ranked seasons and their final standings.
+type SeasonRepo struct {
+	collection *mongo.Collection
+}
+
+// NewSeasonRepo creates a new SeasonRepo with the given MongoDB client, database name, and collection name.
+func NewSeasonRepo(client *mongo.Client, dbName, collectionName string) *SeasonRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &SeasonRepo{
+		collection: collection,
+	}
+}
+
+// Save inserts or replaces a season in the repository.
+func (s *SeasonRepo) Save(ctx context.Context, season *dmn.Season) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": season.Number}
+	opts := options.Replace().SetUpsert(true)
+	_, err := s.collection.ReplaceOne(ctx, filter, season, opts)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+
+	return nil
+}
+
+// Latest retrieves the season with the highest number, without its standings.
+// Returns nil without an error if no season has been recorded yet.
+func (s *SeasonRepo) Latest(ctx context.Context) (*dmn.Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	opts := options.FindOne().
+		SetSort(bson.M{"_id": -1}).
+		SetProjection(bson.M{"standings": 0})
+
+	var season dmn.Season
+	if err := s.collection.FindOne(ctx, bson.M{}, opts).Decode(&season); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return &season, nil
+}
+
+// ByNumber retrieves a season, including its standings, by its number.
+// Returns an error if the season is not found or if an unexpected error occurs.
+func (s *SeasonRepo) ByNumber(ctx context.Context, number int) (*dmn.Season, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var season dmn.Season
+	if err := s.collection.FindOne(ctx, bson.M{"_id": number}).Decode(&season); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("season not found")
+		}
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return &season, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"errors"
+	"regexp"
 	"time"
 
 	dmn "github.com/beka-birhanu/vinom-api/domain"
@@ -10,26 +11,50 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // UserRepo handles the persistence of user models.
+//
+// Reads run under the caller's deadline; the API bounds every request with
+// deadline.Middleware. Single-user writes also cap the context with a short
+// timeout of their own, so a write stuck on a stalled primary fails and can be
+// retried even when the request has time left. SoftResetRatings rewrites every
+// user and has a longer budget.
 type UserRepo struct {
 	collection *mongo.Collection
+	reads      *mongo.Collection // Leaderboard queries; may be served by secondaries
 }
 
 // NewUserRepo creates a new UserRepo with the given MongoDB client, database name, and collection name.
-func NewUserRepo(client *mongo.Client, dbName, collectionName string) *UserRepo {
+// Leaderboard queries use the heavyReads read preference; nil keeps them on the primary.
+func NewUserRepo(client *mongo.Client, dbName, collectionName string, heavyReads *readpref.ReadPref) *UserRepo {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &UserRepo{
 		collection: collection,
+		reads:      readCollection(collection, heavyReads),
 	}
 }
 
+// EnsureIndexes creates the index ByRating, CountAhead and rating ordered listings sort by,
+// and the unique username index that lookups and prefix searches use and that Save
+// relies on to report a username conflict.
+func (u *UserRepo) EnsureIndexes(ctx context.Context) error {
+	_, err := u.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "rating", Value: -1}, {Key: "username", Value: 1}}},
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
 // Save inserts or updates a user in the repository.
 // If the user already exists, it updates the existing record.
 // If the user does not exist, it adds a new record.
-func (u *UserRepo) Save(user *dmn.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+func (u *UserRepo) Save(ctx context.Context, user *dmn.User) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
 	defer cancel()
 
 	filter := bson.M{"_id": user.ID}
@@ -37,9 +62,15 @@ func (u *UserRepo) Save(user *dmn.User) error {
 		"$set": bson.M{
 			"username":     user.Username,
 			"passwordHash": user.PasswordHash,
-			"rating":       user.Rating,
+			"roles":        user.Roles,
 			"updatedAt":    time.Now(),
 		},
+		// Saves are read-modify-writes of profile changes. The rating and placements
+		// change elsewhere meanwhile, and saving a user must not restore them.
+		"$setOnInsert": bson.M{
+			"rating":               user.Rating,
+			"placementMatchesLeft": user.PlacementMatchesLeft,
+		},
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -56,10 +87,7 @@ func (u *UserRepo) Save(user *dmn.User) error {
 
 // ByID retrieves a user by their ID.
 // Returns an error if the user is not found or if an unexpected error occurs.
-func (u *UserRepo) ByID(id uuid.UUID) (*dmn.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
+func (u *UserRepo) ByID(ctx context.Context, id uuid.UUID) (*dmn.User, error) {
 	filter := bson.M{"_id": id}
 	var user dmn.User
 	if err := u.collection.FindOne(ctx, filter).Decode(&user); err != nil {
@@ -71,12 +99,23 @@ func (u *UserRepo) ByID(id uuid.UUID) (*dmn.User, error) {
 	return &user, nil
 }
 
+// ByIDs retrieves the users with the given IDs, skipping IDs without a user.
+func (u *UserRepo) ByIDs(ctx context.Context, ids []uuid.UUID) ([]*dmn.User, error) {
+	cursor, err := u.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	users := make([]*dmn.User, 0, len(ids))
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return users, nil
+}
+
 // ByUsername retrieves a user by their username.
 // Returns an error if the user is not found or if an unexpected error occurs.
-func (u *UserRepo) ByUsername(username string) (*dmn.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
+func (u *UserRepo) ByUsername(ctx context.Context, username string) (*dmn.User, error) {
 	filter := bson.M{"username": username}
 	var user dmn.User
 	if err := u.collection.FindOne(ctx, filter).Decode(&user); err != nil {
@@ -87,3 +126,169 @@ func (u *UserRepo) ByUsername(username string) (*dmn.User, error) {
 	}
 	return &user, nil
 }
+
+// Delete removes a user by their ID.
+// Returns an error if the user is not found or if an unexpected error occurs.
+func (u *UserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id}
+	result, err := u.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// SoftResetRatings moves every rating toward reset.Mean, keeping reset.Keep of
+// the distance, and grants every user reset.PlacementMatches placement matches.
+// Users already reset for the end of season are skipped, so a retry only resets
+// the users an interrupted run missed.
+func (u *UserRepo) SoftResetRatings(ctx context.Context, season int, reset dmn.SeasonReset) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	rating := bson.M{"$toInt": bson.M{"$round": bson.A{
+		bson.M{"$add": bson.A{
+			reset.Mean,
+			bson.M{"$multiply": bson.A{bson.M{"$subtract": bson.A{"$rating", reset.Mean}}, reset.Keep}},
+		}},
+		0,
+	}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"rating":               rating,
+			"placementMatchesLeft": reset.PlacementMatches,
+			"resetSeason":          season,
+			"updatedAt":            time.Now(),
+		}}},
+	}
+
+	filter := bson.M{"resetSeason": bson.M{"$ne": season}}
+	if _, err := u.collection.UpdateMany(ctx, filter, pipeline); err != nil {
+		return errors.New("unexpected error: " + err.Error())
+	}
+	return nil
+}
+
+// UsePlacementMatch consumes one placement match of the user for the given match and
+// reports whether the match is a placement match of the user. A retry for the same
+// match finds it recorded on the user and consumes nothing.
+func (u *UserRepo) UsePlacementMatch(ctx context.Context, id, matchID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":                  id,
+		"placementMatchesLeft": bson.M{"$gt": 0},
+		"lastPlacementMatch":   bson.M{"$ne": matchID},
+	}
+	update := bson.M{
+		"$inc": bson.M{"placementMatchesLeft": -1},
+		"$set": bson.M{"lastPlacementMatch": matchID},
+	}
+	result, err := u.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.New("unexpected error: " + err.Error())
+	}
+	if result.ModifiedCount == 1 {
+		return true, nil
+	}
+
+	consumed, err := u.collection.CountDocuments(ctx, bson.M{"_id": id, "lastPlacementMatch": matchID})
+	if err != nil {
+		return false, errors.New("unexpected error: " + err.Error())
+	}
+	return consumed == 1, nil
+}
+
+// ByRating lists users ordered by rating, highest first, with ties broken by username.
+func (u *UserRepo) ByRating(ctx context.Context, offset, limit int) ([]*dmn.User, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rating", Value: -1}, {Key: "username", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := u.reads.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	users := make([]*dmn.User, 0, limit)
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return users, nil
+}
+
+// List returns a page, starting at 1, of the users matching the filter in the given order,
+// without their password hashes. It is bounded by ctx alone, so callers choose the timeout.
+func (u *UserRepo) List(ctx context.Context, filter dmn.UserFilter, sort dmn.UserSort, page, pageSize int) ([]*dmn.User, error) {
+	if page < 1 || pageSize < 1 {
+		return nil, errors.New("invalid page")
+	}
+
+	query := bson.M{}
+	rating := bson.M{}
+	if filter.MinRating != nil {
+		rating["$gte"] = *filter.MinRating
+	}
+	if filter.MaxRating != nil {
+		rating["$lte"] = *filter.MaxRating
+	}
+	if len(rating) > 0 {
+		query["rating"] = rating
+	}
+	if filter.UsernamePrefix != "" {
+		// An anchored, case sensitive regex can use the username index.
+		query["username"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.UsernamePrefix)}
+	}
+
+	var order bson.D
+	switch sort {
+	case dmn.UserSortRatingDesc:
+		order = bson.D{{Key: "rating", Value: -1}, {Key: "username", Value: 1}}
+	case dmn.UserSortRatingAsc:
+		order = bson.D{{Key: "rating", Value: 1}, {Key: "username", Value: 1}}
+	case dmn.UserSortUsername:
+		order = bson.D{{Key: "username", Value: 1}}
+	default:
+		return nil, errors.New("invalid sort")
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"passwordHash": 0}).
+		SetSort(order).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := u.reads.Find(ctx, query, opts)
+	if err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+
+	users := make([]*dmn.User, 0, pageSize)
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, errors.New("unexpected error: " + err.Error())
+	}
+	return users, nil
+}
+
+// CountAhead counts the users that ByRating orders before the given rating and username.
+func (u *UserRepo) CountAhead(ctx context.Context, rating int, username string) (int64, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"rating": bson.M{"$gt": rating}},
+			bson.M{"rating": rating, "username": bson.M{"$lt": username}},
+		},
+	}
+	count, err := u.reads.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, errors.New("unexpected error: " + err.Error())
+	}
+	return count, nil
+}
@@ -0,0 +1,172 @@
+// Package ulid generates lexicographically sortable identifiers.
+//
+// A ULID is 128 bits: a 48-bit millisecond timestamp followed by 80 random bits,
+// both big-endian. Its byte order therefore matches its creation order, which keeps
+// Mongo indexes on binary IDs append-only. ULIDs convert losslessly to uuid.UUID so
+// they can be stored in the same fields as player UUIDs.
+package ulid
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Crockford's base32 alphabet.
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const encodedLen = 26
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier.
+type ULID [16]byte
+
+// Generator creates ULIDs that are strictly increasing, even within the same millisecond.
+type Generator struct {
+	lastMs uint64
+	last   ULID
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// NewGenerator creates a new monotonic ULID generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		now: time.Now,
+	}
+}
+
+var defaultGenerator = NewGenerator()
+
+// New returns a new ULID from the package level generator.
+func New() ULID {
+	return defaultGenerator.New()
+}
+
+// NewUUID returns a new ULID from the package level generator as a uuid.UUID.
+func NewUUID() uuid.UUID {
+	return defaultGenerator.New().UUID()
+}
+
+// New returns a ULID greater than every ULID previously returned by g.
+func (g *Generator) New() ULID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.lastMs {
+		// Same (or earlier) millisecond: increment the previous random part instead of drawing a new one.
+		if g.increment() {
+			return g.last
+		}
+		// The random part overflowed; move to the next logical millisecond.
+		ms = g.lastMs + 1
+	}
+
+	var id ULID
+	putTime(&id, ms)
+	_, _ = rand.Read(id[6:])
+
+	g.lastMs = ms
+	g.last = id
+	return id
+}
+
+// increment adds one to the random part of the last ULID, reporting false on overflow.
+func (g *Generator) increment() bool {
+	for i := len(g.last) - 1; i >= 6; i-- {
+		g.last[i]++
+		if g.last[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// putTime writes a millisecond timestamp into the first six bytes of id.
+func putTime(id *ULID, ms uint64) {
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// Time returns the creation time encoded in the ULID.
+func (u ULID) Time() time.Time {
+	var ms uint64
+	for i := 0; i < 6; i++ {
+		ms = ms<<8 | uint64(u[i])
+	}
+	return time.UnixMilli(int64(ms))
+}
+
+// UUID returns the ULID's bytes as a uuid.UUID.
+func (u ULID) UUID() uuid.UUID {
+	return uuid.UUID(u)
+}
+
+// FromUUID interprets the bytes of a uuid.UUID as a ULID.
+func FromUUID(id uuid.UUID) ULID {
+	return ULID(id)
+}
+
+// String returns the 26 character Crockford base32 encoding of the ULID.
+func (u ULID) String() string {
+	var out [encodedLen]byte
+	// The encoding covers 130 bits; the two leading bits are always zero.
+	bit := -2
+	for i := range out {
+		var v byte
+		for b := 0; b < 5; b++ {
+			v <<= 1
+			if bit >= 0 && u[bit/8]>>(7-bit%8)&1 == 1 {
+				v |= 1
+			}
+			bit++
+		}
+		out[i] = alphabet[v]
+	}
+	return string(out[:])
+}
+
+// Parse decodes a Crockford base32 ULID string; letters are case-insensitive.
+func Parse(s string) (ULID, error) {
+	var id ULID
+	if len(s) != encodedLen {
+		return id, errors.New("invalid ulid length")
+	}
+
+	bit := -2
+	for i := 0; i < len(s); i++ {
+		v, ok := decodeChar(s[i])
+		if !ok {
+			return id, errors.New("invalid ulid character")
+		}
+		if i == 0 && v > 7 {
+			return id, errors.New("ulid overflows 128 bits")
+		}
+
+		for b := 4; b >= 0; b-- {
+			if bit >= 0 && v>>b&1 == 1 {
+				id[bit/8] |= 1 << (7 - bit%8)
+			}
+			bit++
+		}
+	}
+	return id, nil
+}
+
+// decodeChar maps a base32 character to its value.
+func decodeChar(c byte) (byte, bool) {
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	for v := 0; v < len(alphabet); v++ {
+		if alphabet[v] == c {
+			return byte(v), true
+		}
+	}
+	return 0, false
+}
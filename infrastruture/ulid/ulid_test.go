@@ -0,0 +1,86 @@
+package ulid
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestULID(t *testing.T) {
+	t.Run("String and Parse round trip", func(t *testing.T) {
+		id := New()
+		encoded := id.String()
+		assert.Len(t, encoded, 26)
+
+		decoded, err := Parse(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, id, decoded)
+
+		decoded, err = Parse(strings.ToLower(encoded))
+		assert.NoError(t, err)
+		assert.Equal(t, id, decoded)
+	})
+
+	t.Run("Encode time", func(t *testing.T) {
+		g := NewGenerator()
+		now := time.UnixMilli(1700000000123)
+		g.now = func() time.Time { return now }
+
+		assert.Equal(t, now, g.New().Time())
+	})
+
+	t.Run("Monotonic within the same millisecond", func(t *testing.T) {
+		g := NewGenerator()
+		now := time.Now()
+		g.now = func() time.Time { return now }
+
+		prev := g.New()
+		for range 1000 {
+			next := g.New()
+			assert.Less(t, prev.String(), next.String())
+			assert.Equal(t, -1, compare(prev, next))
+			prev = next
+		}
+	})
+
+	t.Run("Monotonic when the clock goes backwards", func(t *testing.T) {
+		g := NewGenerator()
+		now := time.Now()
+		g.now = func() time.Time { return now }
+		first := g.New()
+
+		now = now.Add(-time.Second)
+		assert.Equal(t, -1, compare(first, g.New()))
+	})
+
+	t.Run("UUID conversion", func(t *testing.T) {
+		id := New()
+		assert.Equal(t, id, FromUUID(id.UUID()))
+	})
+
+	t.Run("Parse invalid", func(t *testing.T) {
+		_, err := Parse("short")
+		assert.Error(t, err)
+
+		_, err = Parse("01ARZ3NDEKTSV4RRFFQ69G5FAU") // U is not in the alphabet
+		assert.Error(t, err)
+
+		_, err = Parse("81ARZ3NDEKTSV4RRFFQ69G5FAV")
+		assert.Error(t, err)
+	})
+}
+
+// compare orders two ULIDs byte-wise.
+func compare(a, b ULID) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -10,25 +11,49 @@ import (
 )
 
 type Auth struct {
-	userRepo  i.UserRepo
-	tokenizer i.Tokenizer
+	userRepo       i.UserRepo
+	replayRepo     i.ReplayRepo
+	matchRepo      i.MatchRepo
+	friendshipRepo i.FriendshipRepo
+	tokenizer      i.Tokenizer
+	contentFilter  i.ContentFilter
+	placements     int
 }
 
-func NewAuthService(ur i.UserRepo, t i.Tokenizer) (i.Authenticator, error) {
+// NewAuthService creates the auth service. New users start with the given number
+// of placement matches, like every player at the start of a season.
+func NewAuthService(ur i.UserRepo, rr i.ReplayRepo, mr i.MatchRepo, fr i.FriendshipRepo, t i.Tokenizer, cf i.ContentFilter, placementMatches int) (i.Authenticator, error) {
+	if placementMatches < 0 {
+		return nil, errors.New("placement matches must not be negative")
+	}
+
 	return &Auth{
-		userRepo:  ur,
-		tokenizer: t,
+		userRepo:       ur,
+		replayRepo:     rr,
+		matchRepo:      mr,
+		friendshipRepo: fr,
+		tokenizer:      t,
+		contentFilter:  cf,
+		placements:     placementMatches,
 	}, nil
 }
 
-func (a *Auth) Register(username, password string) error {
+func (a *Auth) Register(ctx context.Context, username, password string) error {
 	userConfig := dmn.UserConfig{
 		ID:            uuid.New(),
 		Username:      username,
 		PlainPassword: password,
 	}
 
-	_, err := a.userRepo.ByUsername(username)
+	allowed, err := a.contentFilter.Allowed(ctx, username)
+	if err != nil {
+		return errors.New("could not validate username")
+	}
+	if !allowed {
+		return errors.New("username not allowed")
+	}
+
+	_, err = a.userRepo.ByUsername(ctx, username)
 	if err == nil {
 		return errors.New("Username already exist")
 	}
@@ -37,8 +62,9 @@ func (a *Auth) Register(username, password string) error {
 	if err != nil {
 		return err
 	}
+	user.PlacementMatchesLeft = a.placements
 
-	err = a.userRepo.Save(user)
+	err = a.userRepo.Save(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -46,8 +72,8 @@ func (a *Auth) Register(username, password string) error {
 	return nil
 }
 
-func (a *Auth) SignIn(username, password string) (*dmn.User, string, error) {
-	user, err := a.userRepo.ByUsername(username)
+func (a *Auth) SignIn(ctx context.Context, username, password string) (*dmn.User, string, error) {
+	user, err := a.userRepo.ByUsername(ctx, username)
 	if err != nil {
 		return nil, "", errors.New("invalid username or password")
 	}
@@ -56,10 +82,118 @@ func (a *Auth) SignIn(username, password string) (*dmn.User, string, error) {
 		return nil, "", errors.New("invalid username or password")
 	}
 
+	scopes := []string{}
+	if user.HasRole(dmn.RolePlayer) {
+		scopes = append(scopes, "matchmaking")
+	}
+
 	token, err := a.tokenizer.Generate(map[string]interface{}{
 		"userID":   user.ID,
 		"username": user.Username,
+		"roles":    user.GrantedRoles(),
+		"scopes":   scopes,
 	}, 24*time.Hour)
 
 	return user, token, err
 }
+
+func (a *Auth) Profile(ctx context.Context, id uuid.UUID) (*dmn.User, error) {
+	return a.userRepo.ByID(ctx, id)
+}
+
+func (a *Auth) UpdateProfile(ctx context.Context, id uuid.UUID, username string) (*dmn.User, error) {
+	user, err := a.userRepo.ByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Username == username {
+		return user, nil
+	}
+
+	allowed, err := a.contentFilter.Allowed(ctx, username)
+	if err != nil {
+		return nil, errors.New("could not validate username")
+	}
+	if !allowed {
+		return nil, errors.New("username not allowed")
+	}
+
+	_, err = a.userRepo.ByUsername(ctx, username)
+	if err == nil {
+		return nil, errors.New("Username already exist")
+	}
+
+	if err := user.Rename(username); err != nil {
+		return nil, err
+	}
+
+	if err := a.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (a *Auth) ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error {
+	user, err := a.userRepo.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := user.ChangePassword(oldPassword, newPassword); err != nil {
+		return err
+	}
+
+	return a.userRepo.Save(ctx, user)
+}
+
+func (a *Auth) DeleteAccount(ctx context.Context, id uuid.UUID, password string) error {
+	user, err := a.userRepo.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.VerifyPassword(password) {
+		return errors.New("invalid password")
+	}
+
+	// Replays and match history stay intact for the other players, under an ID
+	// that no longer links to the account.
+	anonymousID := uuid.New()
+	if err := a.replayRepo.AnonymizePlayer(ctx, user.ID, anonymousID); err != nil {
+		return err
+	}
+	if err := a.matchRepo.AnonymizePlayer(ctx, user.ID, anonymousID); err != nil {
+		return err
+	}
+
+	if err := a.friendshipRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return a.userRepo.Delete(ctx, user.ID)
+}
+
+func (a *Auth) GrantRole(ctx context.Context, username, role string) error {
+	user, err := a.userRepo.ByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := user.GrantRole(role); err != nil {
+		return err
+	}
+
+	return a.userRepo.Save(ctx, user)
+}
+
+func (a *Auth) ServiceToken(_ context.Context, service string, scopes []string, ttl time.Duration) (string, error) {
+	if service == "" {
+		return "", errors.New("service name is required")
+	}
+
+	return a.tokenizer.Generate(map[string]interface{}{
+		"service": service,
+		"roles":   []string{dmn.RoleService},
+		"scopes":  scopes,
+	}, ttl)
+}
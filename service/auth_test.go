@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/contentfilter"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/beka-birhanu/vinom-api/infrastruture/token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Grant placement matches on registration", func(t *testing.T) {
+		users := repotest.NewInMemoryUserRepo()
+		auth, err := NewAuthService(users, repotest.NewInMemoryReplayRepo(), repotest.NewInMemoryMatchRepo(), repotest.NewInMemoryFriendshipRepo(),
+			token.NewJwtService("secret", "vinom"), contentfilter.NewDefaultWordlist(), 10)
+		assert.NoError(t, err)
+
+		assert.NoError(t, auth.Register(ctx, "abebe", "correct-horse-battery-staple"))
+		user, err := users.ByUsername(ctx, "abebe")
+		assert.NoError(t, err)
+		assert.Equal(t, 10, user.PlacementMatchesLeft)
+
+		assert.EqualError(t, auth.Register(ctx, "abebe", "correct-horse-battery-staple"), "Username already exist")
+	})
+	t.Run("Anonymize the match history of deleted accounts", func(t *testing.T) {
+		users, matches := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryMatchRepo()
+		auth, err := NewAuthService(users, repotest.NewInMemoryReplayRepo(), matches, repotest.NewInMemoryFriendshipRepo(),
+			token.NewJwtService("secret", "vinom"), contentfilter.NewDefaultWordlist(), 10)
+		assert.NoError(t, err)
+
+		// Seeded with a cheap hash; registering would spend seconds in bcrypt.
+		hash, err := bcrypt.GenerateFromPassword([]byte("correct-horse-battery-staple"), bcrypt.MinCost)
+		assert.NoError(t, err)
+		abebe := uuid.New()
+		assert.NoError(t, users.Save(ctx, &dmn.User{ID: abebe, Username: "abebe", PasswordHash: string(hash)}))
+		bekele := saveUser(t, users, "bekele", 1500)
+		match := newMatch(map[uuid.UUID]int{abebe: 3, bekele: 1})
+		match.PlayerIDs = []uuid.UUID{match.Players[0].PlayerID, match.Players[1].PlayerID}
+		assert.NoError(t, matches.Insert(ctx, match))
+
+		assert.NoError(t, auth.DeleteAccount(ctx, abebe, "correct-horse-battery-staple"))
+
+		history, _ := matches.ByPlayer(ctx, abebe, 0, 10)
+		assert.Empty(t, history)
+		history, _ = matches.ByPlayer(ctx, bekele, 0, 10)
+		assert.Len(t, history, 1)
+		assert.NotContains(t, history[0].PlayerIDs, abebe)
+		for _, player := range history[0].Players {
+			assert.NotEqual(t, abebe, player.PlayerID)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+)
+
+// maxFriendships caps the friends and outgoing friend requests of a user.
+// Incoming requests do not count, so others cannot fill up a user's list.
+const maxFriendships = 200
+
+type Friends struct {
+	friendshipRepo i.FriendshipRepo
+	userRepo       i.UserRepo
+}
+
+func NewFriendsService(fr i.FriendshipRepo, ur i.UserRepo) (i.Friends, error) {
+	return &Friends{
+		friendshipRepo: fr,
+		userRepo:       ur,
+	}, nil
+}
+
+func (f *Friends) List(ctx context.Context, userID uuid.UUID) ([]*dmn.Friend, error) {
+	friendships, err := f.friendshipRepo.ByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(friendships))
+	for _, friendship := range friendships {
+		ids = append(ids, friendship.Other(userID))
+	}
+	users, err := f.userRepo.ByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*dmn.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	friends := make([]*dmn.Friend, 0, len(friendships))
+	for _, friendship := range friendships {
+		// Friendships of deleted users are removed with them; skip any left behind.
+		if other, ok := byID[friendship.Other(userID)]; ok {
+			friends = append(friends, newFriend(userID, other, friendship))
+		}
+	}
+	return friends, nil
+}
+
+func (f *Friends) Request(ctx context.Context, userID uuid.UUID, username string) (*dmn.Friend, error) {
+	addressee, err := f.userRepo.ByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := f.friendshipRepo.Between(ctx, userID, addressee.ID)
+	switch {
+	case err == nil:
+		if existing.Status == dmn.FriendshipPending && existing.AddresseeID == userID {
+			return f.accept(ctx, userID, existing)
+		}
+		return nil, errors.New("friend request already exists")
+	case err.Error() != "friendship not found":
+		return nil, err
+	}
+
+	for _, id := range []uuid.UUID{userID, addressee.ID} {
+		if err := f.checkLimit(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	friendship, err := dmn.NewFriendRequest(userID, addressee.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The ID is derived from the pair, so a concurrent request between the same
+	// users fails here instead of creating a second friendship.
+	if err := f.friendshipRepo.Insert(ctx, friendship); err != nil {
+		return nil, err
+	}
+	return newFriend(userID, addressee, friendship), nil
+}
+
+func (f *Friends) Accept(ctx context.Context, userID, friendID uuid.UUID) (*dmn.Friend, error) {
+	friendship, err := f.friendshipRepo.Between(ctx, userID, friendID)
+	if err != nil {
+		return nil, err
+	}
+	return f.accept(ctx, userID, friendship)
+}
+
+func (f *Friends) Remove(ctx context.Context, userID, friendID uuid.UUID) error {
+	friendship, err := f.friendshipRepo.Between(ctx, userID, friendID)
+	if err != nil {
+		return err
+	}
+	return f.friendshipRepo.Delete(ctx, friendship.ID)
+}
+
+func (f *Friends) accept(ctx context.Context, userID uuid.UUID, friendship *dmn.Friendship) (*dmn.Friend, error) {
+	if err := friendship.Accept(userID); err != nil {
+		return nil, err
+	}
+	// The request was incoming, so it does not count toward the addressee's limit yet.
+	if err := f.checkLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := f.friendshipRepo.Accept(ctx, friendship); err != nil {
+		return nil, err
+	}
+	return f.friend(ctx, userID, friendship)
+}
+
+// checkLimit fails if the user has reached maxFriendships.
+func (f *Friends) checkLimit(ctx context.Context, userID uuid.UUID) error {
+	friendships, err := f.friendshipRepo.ByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, friendship := range friendships {
+		if friendship.Status == dmn.FriendshipAccepted || friendship.RequesterID == userID {
+			count++
+		}
+	}
+	if count >= maxFriendships {
+		return errors.New("too many friends")
+	}
+	return nil
+}
+
+// friend describes the friendship from the point of view of userID.
+func (f *Friends) friend(ctx context.Context, userID uuid.UUID, friendship *dmn.Friendship) (*dmn.Friend, error) {
+	other, err := f.userRepo.ByID(ctx, friendship.Other(userID))
+	if err != nil {
+		return nil, err
+	}
+	return newFriend(userID, other, friendship), nil
+}
+
+// newFriend describes the friendship with other from the point of view of userID.
+func newFriend(userID uuid.UUID, other *dmn.User, friendship *dmn.Friendship) *dmn.Friend {
+	since := friendship.CreatedAt
+	if friendship.Status == dmn.FriendshipAccepted {
+		since = friendship.AcceptedAt
+	}
+
+	return &dmn.Friend{
+		UserID:   other.ID,
+		Username: other.Username,
+		Status:   friendship.Status,
+		Incoming: friendship.AddresseeID == userID,
+		Since:    since,
+	}
+}
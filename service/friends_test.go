@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingFriendshipRepo fails every lookup of a friendship between two users.
+type failingFriendshipRepo struct {
+	*repotest.InMemoryFriendshipRepo
+}
+
+func (r failingFriendshipRepo) Between(context.Context, uuid.UUID, uuid.UUID) (*dmn.Friendship, error) {
+	return nil, errors.New("unexpected error: connection reset")
+}
+
+// removingFriendshipRepo removes each friendship right after looking it up, as a concurrent remove would.
+type removingFriendshipRepo struct {
+	*repotest.InMemoryFriendshipRepo
+}
+
+func (r removingFriendshipRepo) Between(ctx context.Context, userID, otherID uuid.UUID) (*dmn.Friendship, error) {
+	friendship, err := r.InMemoryFriendshipRepo.Between(ctx, userID, otherID)
+	if err == nil {
+		_ = r.Delete(ctx, friendship.ID)
+	}
+	return friendship, err
+}
+
+func TestFriends(t *testing.T) {
+	ctx := context.Background()
+	newFriends := func() (*repotest.InMemoryUserRepo, i.Friends) {
+		users := repotest.NewInMemoryUserRepo()
+		friends, _ := NewFriendsService(repotest.NewInMemoryFriendshipRepo(), users)
+		return users, friends
+	}
+
+	t.Run("Request and accept a friendship", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe, bekele := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500)
+
+		sent, err := friends.Request(ctx, abebe, "bekele")
+		assert.NoError(t, err)
+		assert.Equal(t, dmn.FriendshipPending, sent.Status)
+		assert.False(t, sent.Incoming)
+
+		_, err = friends.Request(ctx, abebe, "bekele")
+		assert.EqualError(t, err, "friend request already exists")
+		_, err = friends.Accept(ctx, abebe, bekele)
+		assert.EqualError(t, err, "only the addressee can accept a friend request")
+
+		incoming, err := friends.List(ctx, bekele)
+		assert.NoError(t, err)
+		assert.Len(t, incoming, 1)
+		assert.Equal(t, "abebe", incoming[0].Username)
+		assert.True(t, incoming[0].Incoming)
+
+		accepted, err := friends.Accept(ctx, bekele, abebe)
+		assert.NoError(t, err)
+		assert.Equal(t, dmn.FriendshipAccepted, accepted.Status)
+
+		list, _ := friends.List(ctx, abebe)
+		assert.Equal(t, dmn.FriendshipAccepted, list[0].Status)
+	})
+
+	t.Run("Accept a crossed request", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe := saveUser(t, users, "abebe", 1500)
+		bekele := saveUser(t, users, "bekele", 1500)
+
+		_, err := friends.Request(ctx, abebe, "bekele")
+		assert.NoError(t, err)
+		friend, err := friends.Request(ctx, bekele, "abebe")
+		assert.NoError(t, err)
+		assert.Equal(t, dmn.FriendshipAccepted, friend.Status)
+
+		list, _ := friends.List(ctx, abebe)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("Remove a friendship", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe, bekele := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500)
+
+		_, err := friends.Request(ctx, abebe, "bekele")
+		assert.NoError(t, err)
+		assert.NoError(t, friends.Remove(ctx, bekele, abebe))
+
+		list, _ := friends.List(ctx, abebe)
+		assert.Empty(t, list)
+		assert.EqualError(t, friends.Remove(ctx, bekele, abebe), "friendship not found")
+	})
+
+	t.Run("Create one friendship for concurrent requests", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe, bekele := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500)
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = friends.Request(ctx, abebe, "bekele")
+			}()
+		}
+		wg.Wait()
+
+		list, err := friends.List(ctx, bekele)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("Do not resurrect a friendship removed while accepting", func(t *testing.T) {
+		users := repotest.NewInMemoryUserRepo()
+		friendships := repotest.NewInMemoryFriendshipRepo()
+		friends, _ := NewFriendsService(friendships, users)
+		racing, _ := NewFriendsService(removingFriendshipRepo{friendships}, users)
+		abebe, bekele := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500)
+
+		_, err := friends.Request(ctx, abebe, "bekele")
+		assert.NoError(t, err)
+		_, err = racing.Accept(ctx, bekele, abebe)
+		assert.EqualError(t, err, "friendship not found")
+
+		list, _ := friends.List(ctx, bekele)
+		assert.Empty(t, list)
+	})
+
+	t.Run("Limit the friendships of the addressee", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe := saveUser(t, users, "abebe", 1500)
+		popular := saveUser(t, users, "popular", 1500)
+		for n := range maxFriendships {
+			fan := saveUser(t, users, fmt.Sprintf("fan%d", n), 1500)
+			_, err := friends.Request(ctx, fan, "popular")
+			assert.NoError(t, err)
+			_, err = friends.Accept(ctx, popular, fan)
+			assert.NoError(t, err)
+		}
+
+		_, err := friends.Request(ctx, abebe, "popular")
+		assert.EqualError(t, err, "too many friends")
+		_, err = friends.Request(ctx, popular, "abebe")
+		assert.EqualError(t, err, "too many friends")
+	})
+
+	t.Run("Do not count incoming requests toward the limit", func(t *testing.T) {
+		users, friends := newFriends()
+		saveUser(t, users, "abebe", 1500)
+		popular := saveUser(t, users, "popular", 1500)
+		var fans []uuid.UUID
+		for n := range maxFriendships {
+			fan := saveUser(t, users, fmt.Sprintf("fan%d", n), 1500)
+			_, err := friends.Request(ctx, fan, "popular")
+			assert.NoError(t, err)
+			fans = append(fans, fan)
+		}
+
+		_, err := friends.Request(ctx, popular, "abebe")
+		assert.NoError(t, err)
+		for _, fan := range fans[:maxFriendships-1] {
+			_, err = friends.Accept(ctx, popular, fan)
+			assert.NoError(t, err)
+		}
+		_, err = friends.Accept(ctx, popular, fans[maxFriendships-1])
+		assert.EqualError(t, err, "too many friends")
+	})
+
+	t.Run("Fail requests when the lookup fails", func(t *testing.T) {
+		users := repotest.NewInMemoryUserRepo()
+		friendships := repotest.NewInMemoryFriendshipRepo()
+		friends, _ := NewFriendsService(failingFriendshipRepo{friendships}, users)
+		abebe := saveUser(t, users, "abebe", 1500)
+		saveUser(t, users, "bekele", 1500)
+
+		_, err := friends.Request(ctx, abebe, "bekele")
+		assert.EqualError(t, err, "unexpected error: connection reset")
+		list, _ := friendships.ByUser(ctx, abebe)
+		assert.Empty(t, list)
+	})
+
+	t.Run("Reject befriending yourself and unknown users", func(t *testing.T) {
+		users, friends := newFriends()
+		abebe := saveUser(t, users, "abebe", 1500)
+
+		_, err := friends.Request(ctx, abebe, "abebe")
+		assert.EqualError(t, err, "cannot befriend yourself")
+		_, err = friends.Request(ctx, abebe, "nobody")
+		assert.EqualError(t, err, "user not found")
+	})
+}
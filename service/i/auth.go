@@ -1,10 +1,33 @@
 package i
 
 import (
+	"context"
+	"time"
+
 	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
 )
 
 type Authenticator interface {
-	Register(string, string) error
-	SignIn(string, string) (*dmn.User, string, error)
+	Register(ctx context.Context, username, password string) error
+	SignIn(ctx context.Context, username, password string) (*dmn.User, string, error)
+
+	// Profile returns the user with the given ID.
+	Profile(ctx context.Context, id uuid.UUID) (*dmn.User, error)
+
+	// UpdateProfile changes the username of the user with the given ID.
+	UpdateProfile(ctx context.Context, id uuid.UUID, username string) (*dmn.User, error)
+
+	// ChangePassword replaces the password of the user after verifying the old one.
+	ChangePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error
+
+	// DeleteAccount verifies the password, anonymizes the user's replays, removes
+	// their friendships, and deletes the user.
+	DeleteAccount(ctx context.Context, id uuid.UUID, password string) error
+
+	// GrantRole grants a role to the user with the given username.
+	GrantRole(ctx context.Context, username, role string) error
+
+	// ServiceToken issues a token for another backend service with the given scopes.
+	ServiceToken(ctx context.Context, service string, scopes []string, ttl time.Duration) (string, error)
 }
@@ -0,0 +1,10 @@
+package i
+
+import "context"
+
+// ContentFilter decides whether user supplied text is acceptable.
+type ContentFilter interface {
+	// Allowed reports whether the text may be used.
+	// An error means the filter could not reach a decision.
+	Allowed(ctx context.Context, text string) (bool, error)
+}
@@ -0,0 +1,23 @@
+package i
+
+import (
+	"context"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// Friends manages friend requests and friendships between users.
+type Friends interface {
+	// List returns the friends and pending friend requests of a user.
+	List(ctx context.Context, userID uuid.UUID) ([]*dmn.Friend, error)
+
+	// Request sends a friend request to the user with the given username.
+	// If that user already requested the friendship, it is accepted instead.
+	Request(ctx context.Context, userID uuid.UUID, username string) (*dmn.Friend, error)
+
+	// Accept accepts the pending friend request sent by friendID.
+	Accept(ctx context.Context, userID, friendID uuid.UUID) (*dmn.Friend, error)
+
+	// Remove ends a friendship, or cancels or declines a friend request.
+	Remove(ctx context.Context, userID, friendID uuid.UUID) error
+}
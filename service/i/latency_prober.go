@@ -0,0 +1,18 @@
+package i
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LatencyProber measures player round trip times on the server side.
+type LatencyProber interface {
+	// Start opens a probe for the player and returns its nonce.
+	// A newer probe replaces any probe still open for the same player.
+	Start(playerID uuid.UUID) (string, error)
+	// Finish closes the probe with the given nonce and returns the measured round trip.
+	Finish(playerID uuid.UUID, nonce string) (time.Duration, error)
+	// Latency returns the player's smoothed round trip, if one was measured recently.
+	Latency(playerID uuid.UUID) (time.Duration, bool)
+}
@@ -0,0 +1,19 @@
+package i
+
+import (
+	"context"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// Leaderboard ranks players by rating.
+type Leaderboard interface {
+	// Top returns a page of the leaderboard; pages start at 1.
+	Top(ctx context.Context, page, pageSize int) ([]*dmn.LeaderboardEntry, error)
+
+	// Rank returns the leaderboard entry of a player.
+	Rank(ctx context.Context, playerID uuid.UUID) (*dmn.LeaderboardEntry, error)
+
+	// Around returns the entries within radius places of the given rank.
+	Around(ctx context.Context, rank, radius int) ([]*dmn.LeaderboardEntry, error)
+}
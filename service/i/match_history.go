@@ -0,0 +1,26 @@
+package i
+
+import (
+	"context"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/google/uuid"
+)
+
+// MatchHistory records finished matches and answers questions about past games.
+type MatchHistory interface {
+	// Record stores the result of a finished match, consuming a placement match of
+	// every player that has one left, and returns the recorded match.
+	// A retried report returns the match recorded first with dmn.ErrMatchRecorded,
+	// after finishing the placements if an earlier attempt failed midway.
+	Record(ctx context.Context, match *dmn.MatchResult) (*dmn.MatchResult, error)
+
+	// History returns a page of the matches a player took part in; pages start at 1.
+	History(ctx context.Context, playerID uuid.UUID, page, pageSize int) ([]*dmn.MatchResult, error)
+
+	// HeadToHead summarizes the most recent matches two players played against each other.
+	HeadToHead(ctx context.Context, playerID, opponentID uuid.UUID) (*dmn.HeadToHead, error)
+
+	// PlayedToday counts the matches that ended since midnight UTC.
+	PlayedToday(ctx context.Context) (int64, error)
+}
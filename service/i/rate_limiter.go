@@ -0,0 +1,14 @@
+package i
+
+// RateLimiter decides whether a request identified by a key may proceed.
+type RateLimiter interface {
+	// Allow consumes one unit for the key and reports whether it was available.
+	Allow(key string) bool
+}
+
+// AdjustableRateLimiter is a RateLimiter whose limits can change while it is in use.
+type AdjustableRateLimiter interface {
+	RateLimiter
+	// SetLimits replaces the refill rate per second and the burst size.
+	SetLimits(rate float64, burst int)
+}
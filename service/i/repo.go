@@ -1,6 +1,9 @@
 package i
 
 import (
+	"context"
+	"time"
+
 	dmn "github.com/beka-birhanu/vinom-api/domain"
 	"github.com/google/uuid"
 )
@@ -9,13 +12,136 @@ import (
 type UserRepo interface {
 	// Save inserts or updates a user in the repository.
 	// If the user already exists, it updates the record. Otherwise, it creates a new one.
-	Save(user *dmn.User) error
+	Save(ctx context.Context, user *dmn.User) error
 
 	// ByID retrieves a user by their unique ID.
 	// Returns an error if the user is not found or in case of an unexpected error.
-	ByID(id uuid.UUID) (*dmn.User, error)
+	ByID(ctx context.Context, id uuid.UUID) (*dmn.User, error)
 
 	// ByUsername retrieves a user by their username.
 	// Returns an error if the user is not found or in case of an unexpected error.
-	ByUsername(username string) (*dmn.User, error)
+	ByUsername(ctx context.Context, username string) (*dmn.User, error)
+
+	// ByIDs retrieves the users with the given IDs, in no particular order.
+	// IDs without a user are skipped.
+	ByIDs(ctx context.Context, ids []uuid.UUID) ([]*dmn.User, error)
+
+	// Delete removes a user from the repository.
+	// Returns an error if the user is not found or in case of an unexpected error.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ByRating lists users ordered by rating (highest first), ties broken by username.
+	ByRating(ctx context.Context, offset, limit int) ([]*dmn.User, error)
+
+	// CountAhead counts the users ordered before the given rating and username by ByRating.
+	CountAhead(ctx context.Context, rating int, username string) (int64, error)
+
+	// SoftResetRatings moves every rating toward the reset mean and grants placement
+	// matches for the end of the given season. Users already reset for that season
+	// are skipped, so an interrupted reset can be run again.
+	SoftResetRatings(ctx context.Context, season int, reset dmn.SeasonReset) error
+
+	// UsePlacementMatch consumes a placement match of the user for the given match and
+	// reports whether the match is a placement match of the user. Calling it again for
+	// the same match consumes nothing and reports the same.
+	UsePlacementMatch(ctx context.Context, id, matchID uuid.UUID) (bool, error)
+
+	// List returns a page, starting at 1, of the users matching the filter in the given order.
+	// Password hashes are not loaded.
+	List(ctx context.Context, filter dmn.UserFilter, sort dmn.UserSort, page, pageSize int) ([]*dmn.User, error)
+}
+
+// ReplayRepo defines the interface for match replay persistence operations.
+type ReplayRepo interface {
+	// Save inserts or replaces a replay and its frames in the repository.
+	Save(ctx context.Context, replay *dmn.Replay) error
+
+	// ByID retrieves a replay without its frames.
+	// Returns dmn.ErrReplayNotFound if the replay is not found, or an error in case of an unexpected error.
+	ByID(ctx context.Context, id uuid.UUID) (*dmn.Replay, error)
+
+	// EachFrame calls fn with every frame of a replay in version order and stops at
+	// the first error fn returns.
+	// Returns dmn.ErrReplayNotFound if the replay is not found, or an error in case of an unexpected error.
+	EachFrame(ctx context.Context, id uuid.UUID, fn func(*dmn.ReplayFrame) error) error
+
+	// ByPlayer lists a page of the replays a player took part in, newest first, without their frames.
+	ByPlayer(ctx context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.Replay, error)
+
+	// AnonymizePlayer replaces the player's ID with anonymousID in every replay.
+	AnonymizePlayer(ctx context.Context, playerID, anonymousID uuid.UUID) error
+}
+
+// EventRepo defines the interface for limited-time event persistence operations.
+type EventRepo interface {
+	// Save inserts or replaces an event in the repository.
+	Save(ctx context.Context, event *dmn.Event) error
+
+	// EndingAfter lists running and upcoming events that have not ended by the given time.
+	EndingAfter(ctx context.Context, t time.Time) ([]*dmn.Event, error)
+}
+
+// MatchRepo defines the interface for match result persistence operations.
+type MatchRepo interface {
+	// Insert adds a match result that has not been recorded yet.
+	// Returns dmn.ErrMatchRecorded if a result with the same ID exists.
+	Insert(ctx context.Context, match *dmn.MatchResult) error
+
+	// Save inserts or replaces a match result in the repository.
+	Save(ctx context.Context, match *dmn.MatchResult) error
+
+	// ByID retrieves a match result by its ID.
+	// Returns an error if the match is not found or in case of an unexpected error.
+	ByID(ctx context.Context, id uuid.UUID) (*dmn.MatchResult, error)
+
+	// ByPlayer lists the matches a player took part in, most recently ended first.
+	ByPlayer(ctx context.Context, playerID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error)
+
+	// Between lists the matches both players took part in, most recently ended first.
+	Between(ctx context.Context, playerID, opponentID uuid.UUID, offset, limit int) ([]*dmn.MatchResult, error)
+
+	// CountEndedSince counts the matches that ended at or after the given time.
+	CountEndedSince(ctx context.Context, since time.Time) (int64, error)
+
+	// AnonymizePlayer replaces the player's ID with anonymousID in every match result.
+	AnonymizePlayer(ctx context.Context, playerID, anonymousID uuid.UUID) error
+}
+
+// FriendshipRepo defines the interface for friendship persistence operations.
+type FriendshipRepo interface {
+	// Insert adds a new friendship to the repository.
+	// Returns an error if a friendship with the same ID already exists or in case of an unexpected error.
+	Insert(ctx context.Context, friendship *dmn.Friendship) error
+
+	// Accept stores the acceptance of a friendship that is still pending.
+	// Returns an error if no pending friendship has its ID or in case of an unexpected error.
+	Accept(ctx context.Context, friendship *dmn.Friendship) error
+
+	// Between retrieves the friendship between two users, whoever requested it.
+	// Returns an error if there is none or in case of an unexpected error.
+	Between(ctx context.Context, userID, otherID uuid.UUID) (*dmn.Friendship, error)
+
+	// ByUser lists the friendships and friend requests of a user.
+	ByUser(ctx context.Context, userID uuid.UUID) ([]*dmn.Friendship, error)
+
+	// Delete removes a friendship from the repository.
+	// Returns an error if the friendship is not found or in case of an unexpected error.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteByUser removes every friendship and friend request of a user.
+	DeleteByUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// SeasonRepo defines the interface for ranked season persistence operations.
+type SeasonRepo interface {
+	// Save inserts or replaces a season in the repository.
+	Save(ctx context.Context, season *dmn.Season) error
+
+	// Latest retrieves the season with the highest number.
+	// Returns nil without an error if no season has been recorded yet.
+	Latest(ctx context.Context) (*dmn.Season, error)
+
+	// ByNumber retrieves a season by its number.
+	// Returns an error if the season is not found or in case of an unexpected error.
+	ByNumber(ctx context.Context, number int) (*dmn.Season, error)
 }
@@ -0,0 +1,20 @@
+package i
+
+import (
+	"context"
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+)
+
+// Seasons manages ranked seasons.
+type Seasons interface {
+	// Current returns the running season.
+	Current(ctx context.Context) (*dmn.Season, error)
+
+	// Ended returns an ended season with its final standings.
+	Ended(ctx context.Context, number int) (*dmn.Season, error)
+
+	// End snapshots the leaderboard into the running season, soft-resets every
+	// rating, starts the next season, and returns the ended season.
+	// If a previous End failed part way, End finishes it instead.
+	End(ctx context.Context) (*dmn.Season, error)
+}
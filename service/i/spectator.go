@@ -0,0 +1,14 @@
+package i
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Spectator grants read-only access to running game sessions.
+type Spectator interface {
+	// Spectate returns the public key and socket address of the session the
+	// given player is in, along with a view-only token issued to the viewer.
+	Spectate(ctx context.Context, viewerID, playerID uuid.UUID) ([]byte, string, string, error)
+}
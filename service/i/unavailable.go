@@ -0,0 +1,10 @@
+package i
+
+import "time"
+
+// Unavailable is implemented by errors signalling that a dependency is
+// temporarily unavailable and the call may be retried later.
+type Unavailable interface {
+	error
+	RetryAfter() time.Duration
+}
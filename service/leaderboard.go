@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+)
+
+const (
+	maxLeaderboardPageSize = 100
+	maxLeaderboardRadius   = 50
+	// maxLeaderboardRank is the deepest rank Top and Around list. It keeps offsets far
+	// from overflowing and bounds how many index entries a page has to skip.
+	maxLeaderboardRank = 100000
+)
+
+type Leaderboard struct {
+	userRepo i.UserRepo
+}
+
+func NewLeaderboardService(ur i.UserRepo) (i.Leaderboard, error) {
+	return &Leaderboard{
+		userRepo: ur,
+	}, nil
+}
+
+func (l *Leaderboard) Top(ctx context.Context, page, pageSize int) ([]*dmn.LeaderboardEntry, error) {
+	if page < 1 {
+		return nil, errors.New("page must be positive")
+	}
+	if pageSize < 1 || pageSize > maxLeaderboardPageSize {
+		return nil, errors.New("invalid page size")
+	}
+	if page > maxLeaderboardRank/pageSize+1 {
+		return nil, errors.New("page out of range")
+	}
+
+	return l.entries(ctx, (page-1)*pageSize, pageSize)
+}
+
+func (l *Leaderboard) Rank(ctx context.Context, playerID uuid.UUID) (*dmn.LeaderboardEntry, error) {
+	user, err := l.userRepo.ByID(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ahead, err := l.userRepo.CountAhead(ctx, user.Rating, user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dmn.LeaderboardEntry{
+		Rank:     int(ahead) + 1,
+		PlayerID: user.ID,
+		Username: user.Username,
+		Rating:   user.Rating,
+	}, nil
+}
+
+func (l *Leaderboard) Around(ctx context.Context, rank, radius int) ([]*dmn.LeaderboardEntry, error) {
+	if rank < 1 {
+		return nil, errors.New("rank must be positive")
+	}
+	if rank > maxLeaderboardRank {
+		return nil, errors.New("rank out of range")
+	}
+	if radius < 0 || radius > maxLeaderboardRadius {
+		return nil, errors.New("invalid radius")
+	}
+
+	offset := max(rank-1-radius, 0)
+	return l.entries(ctx, offset, rank+radius-offset)
+}
+
+// entries fetches users starting at offset and assigns their ranks.
+func (l *Leaderboard) entries(ctx context.Context, offset, limit int) ([]*dmn.LeaderboardEntry, error) {
+	users, err := l.userRepo.ByRating(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*dmn.LeaderboardEntry, 0, len(users))
+	for idx, user := range users {
+		entries = append(entries, &dmn.LeaderboardEntry{
+			Rank:     offset + idx + 1,
+			PlayerID: user.ID,
+			Username: user.Username,
+			Rating:   user.Rating,
+		})
+	}
+	return entries, nil
+}
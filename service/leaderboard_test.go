@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	users := repotest.NewInMemoryUserRepo()
+	leaderboard, _ := NewLeaderboardService(users)
+	saveUser(t, users, "chala", 1400)
+	bekele := saveUser(t, users, "bekele", 1500)
+	saveUser(t, users, "abebe", 1500)
+	saveUser(t, users, "dawit", 1300)
+	saveUser(t, users, "eden", 1200)
+
+	usernames := func(page, pageSize int) []string {
+		entries, err := leaderboard.Top(ctx, page, pageSize)
+		assert.NoError(t, err)
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Username)
+		}
+		return names
+	}
+
+	t.Run("Page through the ranking", func(t *testing.T) {
+		assert.Equal(t, []string{"abebe", "bekele"}, usernames(1, 2))
+		assert.Equal(t, []string{"chala", "dawit"}, usernames(2, 2))
+		assert.Equal(t, []string{"eden"}, usernames(3, 2))
+		assert.Empty(t, usernames(4, 2))
+
+		entries, _ := leaderboard.Top(ctx, 2, 2)
+		assert.Equal(t, 3, entries[0].Rank)
+	})
+
+	t.Run("Rank a player, breaking ties by username", func(t *testing.T) {
+		entry, err := leaderboard.Rank(ctx, bekele)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, entry.Rank)
+		assert.Equal(t, 1500, entry.Rating)
+	})
+
+	t.Run("List the entries around a rank", func(t *testing.T) {
+		entries, err := leaderboard.Around(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 3)
+		assert.Equal(t, 1, entries[0].Rank)
+
+		entries, _ = leaderboard.Around(ctx, 4, 1)
+		assert.Equal(t, []int{3, 4, 5}, []int{entries[0].Rank, entries[1].Rank, entries[2].Rank})
+	})
+
+	t.Run("Reject invalid pages", func(t *testing.T) {
+		_, err := leaderboard.Top(ctx, 0, 10)
+		assert.EqualError(t, err, "page must be positive")
+		_, err = leaderboard.Top(ctx, 1, 101)
+		assert.EqualError(t, err, "invalid page size")
+		_, err = leaderboard.Around(ctx, 1, 51)
+		assert.EqualError(t, err, "invalid radius")
+		_, err = leaderboard.Top(ctx, math.MaxInt, 100)
+		assert.EqualError(t, err, "page out of range")
+		_, err = leaderboard.Around(ctx, math.MaxInt, 50)
+		assert.EqualError(t, err, "rank out of range")
+	})
+}
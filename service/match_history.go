@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+)
+
+const (
+	maxHistoryPageSize = 50
+	// headToHeadWindow is how many of the most recent matches between two players
+	// a head to head record summarizes.
+	headToHeadWindow = 1000
+)
+
+type MatchHistory struct {
+	matchRepo i.MatchRepo
+	userRepo  i.UserRepo
+}
+
+func NewMatchHistoryService(mr i.MatchRepo, ur i.UserRepo) (i.MatchHistory, error) {
+	return &MatchHistory{
+		matchRepo: mr,
+		userRepo:  ur,
+	}, nil
+}
+
+func (h *MatchHistory) Record(ctx context.Context, match *dmn.MatchResult) (*dmn.MatchResult, error) {
+	if err := match.Validate(); err != nil {
+		return nil, err
+	}
+
+	// PlayerIDs duplicates the players' IDs so that matches can be queried by player.
+	match.PlayerIDs = make([]uuid.UUID, 0, len(match.Players))
+	for idx := range match.Players {
+		match.PlayerIDs = append(match.PlayerIDs, match.Players[idx].PlayerID)
+		match.Players[idx].Placement = false
+	}
+	match.PlacementsApplied = false
+
+	// Reported results may be retried, also concurrently. Inserting first lets
+	// exactly one report through; the others finish what it may have left undone.
+	err := h.matchRepo.Insert(ctx, match)
+	if errors.Is(err, dmn.ErrMatchRecorded) {
+		recorded, err := h.matchRepo.ByID(ctx, match.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !recorded.PlacementsApplied {
+			if err := h.applyPlacements(ctx, recorded); err != nil {
+				return nil, err
+			}
+		}
+		return recorded, dmn.ErrMatchRecorded
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.applyPlacements(ctx, match); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// applyPlacements consumes a placement match of every player that has one left and
+// marks the match done. Placements are consumed at most once per player and match,
+// so the step can be run again after a failure.
+func (h *MatchHistory) applyPlacements(ctx context.Context, match *dmn.MatchResult) error {
+	for idx := range match.Players {
+		player := &match.Players[idx]
+		placement, err := h.userRepo.UsePlacementMatch(ctx, player.PlayerID, match.ID)
+		if err != nil {
+			return err
+		}
+		player.Placement = placement
+	}
+
+	match.PlacementsApplied = true
+	return h.matchRepo.Save(ctx, match)
+}
+
+func (h *MatchHistory) History(ctx context.Context, playerID uuid.UUID, page, pageSize int) ([]*dmn.MatchResult, error) {
+	if page < 1 {
+		return nil, errors.New("page must be positive")
+	}
+	if pageSize < 1 || pageSize > maxHistoryPageSize {
+		return nil, errors.New("invalid page size")
+	}
+
+	return h.matchRepo.ByPlayer(ctx, playerID, (page-1)*pageSize, pageSize)
+}
+
+func (h *MatchHistory) HeadToHead(ctx context.Context, playerID, opponentID uuid.UUID) (*dmn.HeadToHead, error) {
+	if playerID == opponentID {
+		return nil, errors.New("opponent must be another player")
+	}
+
+	matches, err := h.matchRepo.Between(ctx, playerID, opponentID, 0, headToHeadWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dmn.HeadToHead{
+		PlayerID:   playerID,
+		OpponentID: opponentID,
+	}
+	for _, m := range matches {
+		player, opponent := m.Player(playerID), m.Player(opponentID)
+		if player == nil || opponent == nil {
+			continue
+		}
+
+		result.Matches++
+		switch {
+		case player.Score > opponent.Score:
+			result.Wins++
+		case player.Score < opponent.Score:
+			result.Losses++
+		default:
+			result.Draws++
+		}
+	}
+	return result, nil
+}
+
+func (h *MatchHistory) PlayedToday(ctx context.Context) (int64, error) {
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	return h.matchRepo.CountEndedSince(ctx, midnight)
+}
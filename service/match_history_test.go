@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// saveUser stores a user with the given rating and returns its ID.
+func saveUser(t *testing.T, users *repotest.InMemoryUserRepo, username string, rating int) uuid.UUID {
+	id := uuid.New()
+	assert.NoError(t, users.Save(context.Background(), &dmn.User{ID: id, Username: username, Rating: rating, PasswordHash: "hash"}))
+	return id
+}
+
+// newMatch builds a finished match between the players with the given scores.
+func newMatch(scores map[uuid.UUID]int) *dmn.MatchResult {
+	match := &dmn.MatchResult{
+		ID:        uuid.New(),
+		StartedAt: time.Now().Add(-time.Minute),
+		EndedAt:   time.Now(),
+	}
+	for id, score := range scores {
+		match.Players = append(match.Players, dmn.MatchPlayer{PlayerID: id, Score: score})
+	}
+	return match
+}
+
+// failingPlacementRepo fails consuming a placement of failOn failFor times.
+type failingPlacementRepo struct {
+	*repotest.InMemoryUserRepo
+	failOn  uuid.UUID
+	failFor int
+}
+
+func (r *failingPlacementRepo) UsePlacementMatch(ctx context.Context, id, matchID uuid.UUID) (bool, error) {
+	if id == r.failOn && r.failFor > 0 {
+		r.failFor--
+		return false, errors.New("unexpected error: connection reset")
+	}
+	return r.InMemoryUserRepo.UsePlacementMatch(ctx, id, matchID)
+}
+
+func TestMatchHistory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Record a match once and consume placements once", func(t *testing.T) {
+		users, matches := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryMatchRepo()
+		history, _ := NewMatchHistoryService(matches, users)
+		abebe, bekele := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500)
+		assert.NoError(t, users.SoftResetRatings(ctx, 1, dmn.SeasonReset{Mean: 1500, Keep: 1, PlacementMatches: 1}))
+
+		match := newMatch(map[uuid.UUID]int{abebe: 3, bekele: 1})
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				retry := *match
+				retry.Players = append([]dmn.MatchPlayer(nil), match.Players...)
+				_, err := history.Record(ctx, &retry)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		inserted := 0
+		for err := range errs {
+			if err == nil {
+				inserted++
+				continue
+			}
+			assert.EqualError(t, err, "match already recorded")
+		}
+		assert.Equal(t, 1, inserted)
+
+		recorded, err := matches.ByID(ctx, match.ID)
+		assert.NoError(t, err)
+		assert.True(t, recorded.PlacementsApplied)
+		assert.ElementsMatch(t, []uuid.UUID{abebe, bekele}, recorded.PlayerIDs)
+		assert.True(t, recorded.Player(abebe).Placement)
+
+		// The only placement match left was consumed by the recorded report.
+		stored, err := users.ByID(ctx, abebe)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, stored.PlacementMatchesLeft)
+	})
+
+	t.Run("Finish placements on a retry after a failure", func(t *testing.T) {
+		users := &failingPlacementRepo{InMemoryUserRepo: repotest.NewInMemoryUserRepo(), failFor: 1}
+		matches := repotest.NewInMemoryMatchRepo()
+		history, _ := NewMatchHistoryService(matches, users)
+		abebe, bekele := saveUser(t, users.InMemoryUserRepo, "abebe", 1500), saveUser(t, users.InMemoryUserRepo, "bekele", 1500)
+		assert.NoError(t, users.SoftResetRatings(ctx, 1, dmn.SeasonReset{Mean: 1500, Keep: 1, PlacementMatches: 2}))
+		users.failOn = bekele
+
+		match := newMatch(map[uuid.UUID]int{abebe: 3, bekele: 1})
+		_, err := history.Record(ctx, match)
+		assert.Error(t, err)
+
+		retry := newMatch(map[uuid.UUID]int{abebe: 3, bekele: 1})
+		retry.ID = match.ID
+		recorded, err := history.Record(ctx, retry)
+		assert.ErrorIs(t, err, dmn.ErrMatchRecorded)
+		assert.True(t, recorded.PlacementsApplied)
+		assert.True(t, recorded.Player(abebe).Placement)
+		assert.True(t, recorded.Player(bekele).Placement)
+
+		for _, id := range []uuid.UUID{abebe, bekele} {
+			stored, _ := users.ByID(ctx, id)
+			assert.Equal(t, 1, stored.PlacementMatchesLeft, "each player consumes one placement")
+		}
+	})
+
+	t.Run("Count head to head results", func(t *testing.T) {
+		users, matches := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryMatchRepo()
+		history, _ := NewMatchHistoryService(matches, users)
+		abebe, bekele, chala := saveUser(t, users, "abebe", 1500), saveUser(t, users, "bekele", 1500), saveUser(t, users, "chala", 1500)
+
+		for _, scores := range []map[uuid.UUID]int{
+			{abebe: 3, bekele: 1},
+			{abebe: 3, bekele: 1, chala: 5},
+			{abebe: 0, bekele: 2},
+			{abebe: 2, bekele: 2},
+			{abebe: 9, chala: 1},
+		} {
+			_, err := history.Record(ctx, newMatch(scores))
+			assert.NoError(t, err)
+		}
+
+		record, err := history.HeadToHead(ctx, abebe, bekele)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, record.Matches)
+		assert.Equal(t, 2, record.Wins)
+		assert.Equal(t, 1, record.Losses)
+		assert.Equal(t, 1, record.Draws)
+
+		_, err = history.HeadToHead(ctx, abebe, abebe)
+		assert.EqualError(t, err, "opponent must be another player")
+	})
+
+	t.Run("Page through a player's history", func(t *testing.T) {
+		users, matches := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryMatchRepo()
+		history, _ := NewMatchHistoryService(matches, users)
+		abebe := saveUser(t, users, "abebe", 1500)
+		for range 3 {
+			_, err := history.Record(ctx, newMatch(map[uuid.UUID]int{abebe: 1}))
+			assert.NoError(t, err)
+		}
+
+		page, err := history.History(ctx, abebe, 1, 2)
+		assert.NoError(t, err)
+		assert.Len(t, page, 2)
+		page, _ = history.History(ctx, abebe, 2, 2)
+		assert.Len(t, page, 1)
+
+		_, err = history.History(ctx, abebe, 0, 2)
+		assert.EqualError(t, err, "page must be positive")
+		_, err = history.History(ctx, abebe, 1, 51)
+		assert.EqualError(t, err, "invalid page size")
+	})
+
+	t.Run("Count the matches played today", func(t *testing.T) {
+		users, matches := repotest.NewInMemoryUserRepo(), repotest.NewInMemoryMatchRepo()
+		history, _ := NewMatchHistoryService(matches, users)
+		abebe := saveUser(t, users, "abebe", 1500)
+		_, err := history.Record(ctx, newMatch(map[uuid.UUID]int{abebe: 1}))
+		assert.NoError(t, err)
+		yesterday := newMatch(map[uuid.UUID]int{abebe: 1})
+		yesterday.StartedAt, yesterday.EndedAt = yesterday.StartedAt.Add(-48*time.Hour), yesterday.EndedAt.Add(-48*time.Hour)
+		_, err = history.Record(ctx, yesterday)
+		assert.NoError(t, err)
+
+		played, err := history.PlayedToday(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), played)
+	})
+
+	t.Run("Reject invalid matches", func(t *testing.T) {
+		history, _ := NewMatchHistoryService(repotest.NewInMemoryMatchRepo(), repotest.NewInMemoryUserRepo())
+
+		_, err := history.Record(ctx, newMatch(nil))
+		assert.EqualError(t, err, "match has no players")
+
+		match := newMatch(map[uuid.UUID]int{uuid.New(): 1})
+		match.EndedAt = match.StartedAt.Add(-time.Second)
+		_, err = history.Record(ctx, match)
+		assert.EqualError(t, err, "match ended before it started")
+
+		match = newMatch(map[uuid.UUID]int{uuid.New(): 1})
+		match.Players = append(match.Players, match.Players[0])
+		_, err = history.Record(ctx, match)
+		assert.EqualError(t, err, "player listed twice")
+	})
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/service/i"
+)
+
+type Seasons struct {
+	seasonRepo    i.SeasonRepo
+	userRepo      i.UserRepo
+	reset         dmn.SeasonReset
+	standingsSize int
+}
+
+// NewSeasonsService creates the season service. Ending a season resets ratings as
+// described by reset and keeps the top standingsSize players as final standings.
+func NewSeasonsService(sr i.SeasonRepo, ur i.UserRepo, reset dmn.SeasonReset, standingsSize int) (i.Seasons, error) {
+	if reset.Keep < 0 || reset.Keep > 1 {
+		return nil, errors.New("kept rating fraction must be between 0 and 1")
+	}
+	if reset.PlacementMatches < 0 || standingsSize < 0 {
+		return nil, errors.New("placement matches and standings size must not be negative")
+	}
+
+	return &Seasons{
+		seasonRepo:    sr,
+		userRepo:      ur,
+		reset:         reset,
+		standingsSize: standingsSize,
+	}, nil
+}
+
+func (s *Seasons) Current(ctx context.Context) (*dmn.Season, error) {
+	season, err := s.seasonRepo.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if season == nil {
+		// The first season runs from the start until it is first ended.
+		return &dmn.Season{Number: 1}, nil
+	}
+	return season, nil
+}
+
+func (s *Seasons) Ended(ctx context.Context, number int) (*dmn.Season, error) {
+	season, err := s.seasonRepo.ByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if !season.Ended() {
+		return nil, errors.New("season has not ended")
+	}
+	return season, nil
+}
+
+func (s *Seasons) End(ctx context.Context) (*dmn.Season, error) {
+	season, err := s.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An ended season is only current when a previous End was interrupted. Its
+	// standings are kept and the remaining steps are resumed.
+	if season.Ended() {
+		// Latest does not load the standings.
+		season, err = s.seasonRepo.ByNumber(ctx, season.Number)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		users, err := s.userRepo.ByRating(ctx, 0, s.standingsSize)
+		if err != nil {
+			return nil, err
+		}
+
+		season.EndedAt = time.Now()
+		season.Standings = make([]dmn.SeasonStanding, 0, len(users))
+		for idx, user := range users {
+			season.Standings = append(season.Standings, dmn.SeasonStanding{
+				Rank:     idx + 1,
+				PlayerID: user.ID,
+				Username: user.Username,
+				Rating:   user.Rating,
+			})
+		}
+		if err := s.seasonRepo.Save(ctx, season); err != nil {
+			return nil, err
+		}
+	}
+
+	// Users already reset for this season are skipped, so a retry never resets twice.
+	if err := s.userRepo.SoftResetRatings(ctx, season.Number, s.reset); err != nil {
+		return nil, err
+	}
+
+	next := &dmn.Season{Number: season.Number + 1, StartedAt: season.EndedAt}
+	if err := s.seasonRepo.Save(ctx, next); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dmn "github.com/beka-birhanu/vinom-api/domain"
+	"github.com/beka-birhanu/vinom-api/infrastruture/repo/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingResetRepo fails the next soft reset while fail is set.
+type failingResetRepo struct {
+	*repotest.InMemoryUserRepo
+	fail bool
+}
+
+func (r *failingResetRepo) SoftResetRatings(ctx context.Context, season int, reset dmn.SeasonReset) error {
+	if r.fail {
+		r.fail = false
+		return errors.New("unexpected error: connection reset")
+	}
+	return r.InMemoryUserRepo.SoftResetRatings(ctx, season, reset)
+}
+
+func TestSeasons(t *testing.T) {
+	ctx := context.Background()
+	reset := dmn.SeasonReset{Mean: 1400, Keep: 0.5, PlacementMatches: 3}
+
+	t.Run("End a season with standings and a soft reset", func(t *testing.T) {
+		users := repotest.NewInMemoryUserRepo()
+		seasons, err := NewSeasonsService(repotest.NewInMemorySeasonRepo(), users, reset, 2)
+		assert.NoError(t, err)
+		abebe := saveUser(t, users, "abebe", 1801)
+		saveUser(t, users, "bekele", 1200)
+		saveUser(t, users, "chala", 1000)
+
+		ended, err := seasons.End(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, ended.Number)
+		assert.Len(t, ended.Standings, 2)
+		assert.Equal(t, dmn.SeasonStanding{Rank: 1, PlayerID: abebe, Username: "abebe", Rating: 1801}, ended.Standings[0])
+
+		stored, _ := users.ByID(ctx, abebe)
+		assert.Equal(t, 1601, stored.Rating) // 1400 + 401 * 0.5, rounded
+		assert.Equal(t, 3, stored.PlacementMatchesLeft)
+
+		current, err := seasons.Current(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, current.Number)
+		assert.False(t, current.Ended())
+
+		_, err = seasons.Ended(ctx, 2)
+		assert.EqualError(t, err, "season has not ended")
+	})
+
+	t.Run("Resume an interrupted end without resetting twice", func(t *testing.T) {
+		users := &failingResetRepo{InMemoryUserRepo: repotest.NewInMemoryUserRepo(), fail: true}
+		seasons, _ := NewSeasonsService(repotest.NewInMemorySeasonRepo(), users, reset, 10)
+		abebe := saveUser(t, users.InMemoryUserRepo, "abebe", 1800)
+
+		_, err := seasons.End(ctx)
+		assert.Error(t, err)
+
+		ended, err := seasons.End(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, ended.Number)
+		assert.Equal(t, 1800, ended.Standings[0].Rating)
+
+		stored, _ := users.ByID(ctx, abebe)
+		assert.Equal(t, 1600, stored.Rating)
+
+		// The next End ends season 2 and resets again.
+		ended, err = seasons.End(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, ended.Number)
+		assert.Equal(t, 1600, ended.Standings[0].Rating)
+		stored, _ = users.ByID(ctx, abebe)
+		assert.Equal(t, 1500, stored.Rating)
+	})
+
+	t.Run("Reject invalid resets", func(t *testing.T) {
+		_, err := NewSeasonsService(repotest.NewInMemorySeasonRepo(), repotest.NewInMemoryUserRepo(), dmn.SeasonReset{Keep: 1.5}, 10)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beka-birhanu/vinom-api/service/i"
+	"github.com/google/uuid"
+)
+
+const (
+	// SpectatorClaim marks a token as view-only for the socket authenticator.
+	SpectatorClaim = "spectator"
+	// SessionPlayerClaim holds the ID of the player whose session is watched.
+	SessionPlayerClaim = "sessionPlayerID"
+	// SpectatorAudience is the audience of spectator tokens. Only the game socket
+	// accepts them; the REST API rejects every token issued for an audience.
+	SpectatorAudience = "game-socket"
+
+	spectatorTokenTTL = time.Hour
+)
+
+type Spectator struct {
+	gameSessionManager i.GameSessionManager
+	tokenizer          i.Tokenizer
+}
+
+func NewSpectatorService(gsm i.GameSessionManager, t i.Tokenizer) (i.Spectator, error) {
+	return &Spectator{
+		gameSessionManager: gsm,
+		tokenizer:          t,
+	}, nil
+}
+
+func (s *Spectator) Spectate(ctx context.Context, viewerID, playerID uuid.UUID) ([]byte, string, string, error) {
+	if viewerID == playerID {
+		return nil, "", "", errors.New("players can not spectate their own match")
+	}
+
+	pubKey, socketAddr, err := s.gameSessionManager.SessionInfo(ctx, playerID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	token, err := s.tokenizer.Generate(map[string]interface{}{
+		"userID":           viewerID,
+		SessionPlayerClaim: playerID,
+		SpectatorClaim:     true,
+		"aud":              SpectatorAudience,
+	}, spectatorTokenTTL)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return pubKey, socketAddr, token, nil
+}